@@ -6,6 +6,7 @@ import (
 
 	"github.com/idanyas/go-pterodactyl/models"
 	"github.com/idanyas/go-pterodactyl/pagination"
+	"github.com/idanyas/go-pterodactyl/transport"
 )
 
 // PaginatorClient defines the interface required by the Paginator.
@@ -78,3 +79,20 @@ type client struct {
 func New(c PaginatorClient) ApplicationClient {
 	return &client{client: c}
 }
+
+// externalGuard returns a retry guard for a create request that carries an
+// external ID. Retries are allowed while the resource at path does not exist.
+// If the guard finds it, found is set and the resource is decoded into v.
+func (c *client) externalGuard(path string, found *bool, v interface{}) transport.RetryGuardFunc {
+	return func(ctx context.Context) (bool, error) {
+		resp, err := c.client.Do(ctx, http.MethodGet, path, nil, v)
+		if err == nil {
+			*found = true
+			return false, nil
+		}
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return true, nil
+		}
+		return false, err
+	}
+}
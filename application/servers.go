@@ -7,6 +7,7 @@ import (
 
 	"github.com/idanyas/go-pterodactyl/models"
 	"github.com/idanyas/go-pterodactyl/pagination"
+	"github.com/idanyas/go-pterodactyl/transport"
 )
 
 // CreateServerRequest defines the request body for creating a new server.
@@ -111,12 +112,25 @@ func (c *client) GetServerExternal(ctx context.Context, externalID string) (*mod
 }
 
 // CreateServer creates a new server.
+//
+// Create requests are not retried after a network error or 5xx response unless
+// req.ExternalID is set. In that case the server is looked up by its external
+// ID before each retry, and an existing server is returned instead of creating
+// a duplicate.
 func (c *client) CreateServer(ctx context.Context, req CreateServerRequest) (*models.Server, error) {
-	var response struct {
+	var response, existing struct {
 		Attributes models.Server `json:"attributes"`
 	}
+	var found bool
+	if req.ExternalID != "" {
+		path := fmt.Sprintf("application/servers/external/%s", req.ExternalID)
+		ctx = transport.WithRequestOptions(ctx, transport.RetryGuard(c.externalGuard(path, &found, &existing)))
+	}
 	_, err := c.client.Do(ctx, http.MethodPost, "application/servers", req, &response)
 	if err != nil {
+		if found {
+			return &existing.Attributes, nil
+		}
 		return nil, fmt.Errorf("failed to create server: %w", err)
 	}
 	return &response.Attributes, nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/idanyas/go-pterodactyl"
 	"github.com/idanyas/go-pterodactyl/application"
@@ -62,3 +63,58 @@ func TestServers_DeleteServer_Force(t *testing.T) {
 		t.Fatalf("DeleteServer(force) returned error: %v", err)
 	}
 }
+
+func TestServers_CreateServer_RetryGuard(t *testing.T) {
+	tests := []struct {
+		name       string
+		externalID string
+		exists     bool
+		wantPosts  int
+		wantErr    bool
+	}{
+		{name: "no external ID", wantPosts: 1, wantErr: true},
+		{name: "created by first attempt", externalID: "game-1", exists: true, wantPosts: 1},
+		{name: "not created", externalID: "game-1", wantPosts: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux, serverURL, teardown := setup()
+			defer teardown()
+
+			var posts int
+			mux.HandleFunc("/api/application/servers", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodPost)
+				posts++
+				w.WriteHeader(http.StatusBadGateway)
+			})
+			mux.HandleFunc("/api/application/servers/external/game-1", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodGet)
+				if !tt.exists {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(`{"object":"server","attributes":{"id":7,"external_id":"game-1"}}`))
+			})
+
+			client, _ := pterodactyl.New(serverURL,
+				pterodactyl.WithAPIKey("test-key"),
+				pterodactyl.WithRetryWait(time.Millisecond, time.Millisecond),
+			)
+			appClient := application.New(client)
+
+			server, err := appClient.CreateServer(context.Background(), application.CreateServerRequest{
+				Name:       "Lobby",
+				ExternalID: tt.externalID,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateServer error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if posts != tt.wantPosts {
+				t.Errorf("got %d POST requests, want %d", posts, tt.wantPosts)
+			}
+			if tt.exists && server.ID != 7 {
+				t.Errorf("CreateServer returned server %d, want the existing server 7", server.ID)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/idanyas/go-pterodactyl/models"
 	"github.com/idanyas/go-pterodactyl/pagination"
+	"github.com/idanyas/go-pterodactyl/transport"
 )
 
 // CreateUserRequest defines the request body for creating a new user.
@@ -72,12 +73,25 @@ func (c *client) GetUserExternal(ctx context.Context, externalID string) (*model
 }
 
 // CreateUser creates a new user account.
+//
+// Create requests are not retried after a network error or 5xx response unless
+// req.ExternalID is set. In that case the user is looked up by its external
+// ID before each retry, and an existing user is returned instead of creating
+// a duplicate.
 func (c *client) CreateUser(ctx context.Context, req CreateUserRequest) (*models.User, error) {
-	var response struct {
+	var response, existing struct {
 		Attributes models.User `json:"attributes"`
 	}
+	var found bool
+	if req.ExternalID != "" {
+		path := fmt.Sprintf("application/users/external/%s", req.ExternalID)
+		ctx = transport.WithRequestOptions(ctx, transport.RetryGuard(c.externalGuard(path, &found, &existing)))
+	}
 	_, err := c.client.Do(ctx, http.MethodPost, "application/users", req, &response)
 	if err != nil {
+		if found {
+			return &existing.Attributes, nil
+		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 	return &response.Attributes, nil
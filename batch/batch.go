@@ -0,0 +1,204 @@
+// Package batch runs an operation over many items with bounded concurrency,
+// for mass operations such as suspending a list of servers:
+//
+//	err := batch.Each(ctx, serverIDs, func(ctx context.Context, id int) error {
+//		return c.Application().SuspendServer(ctx, id)
+//	}, batch.Options{Concurrency: 8})
+//
+// Requests made by the operations go through the client's transport, so they
+// are paced by its rate limiter and retried after 429 responses like any other
+// request. The concurrency only bounds how many operations run at once.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultConcurrency = 4
+	maxErrorsInMessage = 10
+)
+
+// Options configures a batch run.
+type Options struct {
+	// Concurrency is the maximum number of operations running at once.
+	// Defaults to 4.
+	Concurrency int
+	// StopOnError stops starting operations after the first one fails.
+	// Operations already running are allowed to finish; the remaining items
+	// are skipped.
+	StopOnError bool
+	// OnProgress is called after each operation finishes. Calls are made one
+	// at a time, so the function needs no locking of its own.
+	OnProgress func(p Progress)
+}
+
+// Progress describes a batch after one of its operations finished.
+type Progress struct {
+	// Index is the position of the item that just finished, and Err its
+	// error, if any.
+	Index int
+	Err   error
+	// Completed is the number of finished operations, including failed ones,
+	// Failed the number of failed ones and Total the number of items.
+	Completed int
+	Failed    int
+	Total     int
+}
+
+// Result is the outcome of the operation for one item.
+type Result[T, R any] struct {
+	Item  T
+	Value R
+	Err   error
+	// Skipped reports that the operation was not run for the item, because
+	// the batch stopped after an error or its context was cancelled.
+	Skipped bool
+}
+
+// ItemError is the error of the operation for one item.
+type ItemError struct {
+	// Index is the position of the item in the batch.
+	Index int
+	// Item is the item, for use in messages.
+	Item any
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d (%v): %v", e.Index, e.Item, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// Errors holds the errors of the items that failed, in item order. Like the
+// errors returned by errors.Join, it matches any of them with errors.Is and
+// errors.As.
+type Errors []*ItemError
+
+// Error implements the error interface. Only the first ten errors are listed.
+func (e Errors) Error() string {
+	n := min(len(e), maxErrorsInMessage)
+	msgs := make([]string, n)
+	for i, err := range e[:n] {
+		msgs[i] = err.Error()
+	}
+	msg := fmt.Sprintf("%d item(s) failed: %s", len(e), strings.Join(msgs, "; "))
+	if len(e) > n {
+		msg += fmt.Sprintf("; and %d more", len(e)-n)
+	}
+	return msg
+}
+
+// Unwrap returns the per-item errors.
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Run calls op for every item, running at most opts.Concurrency operations at
+// once, and returns their results in item order. If some operations fail, the
+// results are returned together with an Errors value describing each failure.
+// If ctx is cancelled, the items not yet started are skipped and, unless some
+// operations failed, the context error is returned.
+func Run[T, R any](ctx context.Context, items []T, op func(ctx context.Context, item T) (R, error), opts Options) ([]Result[T, R], error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	results := make([]Result[T, R], len(items))
+	for i, item := range items {
+		results[i] = Result[T, R]{Item: item, Skipped: true}
+	}
+
+	var (
+		mu       sync.Mutex
+		stopped  bool
+		progress = Progress{Total: len(items)}
+	)
+	finish := func(i int, value R, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i].Value, results[i].Err, results[i].Skipped = value, err, false
+		progress.Index, progress.Err = i, err
+		progress.Completed++
+		if err != nil {
+			progress.Failed++
+			stopped = stopped || opts.StopOnError
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	}
+	stopping := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return stopped
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				// An index may have been handed over while another operation
+				// was failing; leave it skipped.
+				if stopping() || ctx.Err() != nil {
+					continue
+				}
+				value, err := op(ctx, items[i])
+				finish(i, value, err)
+			}
+		}()
+	}
+dispatch:
+	for i := range items {
+		if stopping() || ctx.Err() != nil {
+			break
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	var failed Errors
+	for i, r := range results {
+		if r.Err != nil {
+			failed = append(failed, &ItemError{Index: i, Item: r.Item, Err: r.Err})
+		}
+	}
+	if failed != nil {
+		return results, failed
+	}
+	if err := ctx.Err(); err != nil && progress.Completed < len(items) {
+		return results, err
+	}
+	return results, nil
+}
+
+// Each calls op for every item like Run, for operations that return no value.
+// It returns an Errors value describing each failure, or the context error if
+// ctx was cancelled before every item was started.
+func Each[T any](ctx context.Context, items []T, op func(ctx context.Context, item T) error, opts Options) error {
+	_, err := Run(ctx, items, func(ctx context.Context, item T) (struct{}, error) {
+		return struct{}{}, op(ctx, item)
+	}, opts)
+	return err
+}
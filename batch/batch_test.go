@@ -0,0 +1,177 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl"
+)
+
+func TestRun(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	var running, peak int32
+	var progress []Progress
+	results, err := Run(context.Background(), items, func(ctx context.Context, n int) (int, error) {
+		cur := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if cur <= p || atomic.CompareAndSwapInt32(&peak, p, cur) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if n%3 == 0 {
+			return 0, fmt.Errorf("bad %d", n)
+		}
+		return n * n, nil
+	}, Options{Concurrency: 3, OnProgress: func(p Progress) {
+		progress = append(progress, p)
+	}})
+
+	if peak > 3 {
+		t.Errorf("ran %d operations at once, want at most 3", peak)
+	}
+	for i, r := range results {
+		if r.Item != items[i] || r.Skipped {
+			t.Errorf("result %d = %+v, want item %d run", i, r, items[i])
+		}
+		if r.Item%3 != 0 && (r.Value != r.Item*r.Item || r.Err != nil) {
+			t.Errorf("result %d = %+v, want %d", i, r, r.Item*r.Item)
+		}
+	}
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Index != 2 || errs[1].Index != 5 {
+		t.Fatalf("Run() error = %v, want failures of items 2 and 5", err)
+	}
+	if !strings.Contains(err.Error(), "2 item(s) failed: item 2 (3): bad 3; item 5 (6): bad 6") {
+		t.Errorf("Run() error message = %q", err)
+	}
+
+	if len(progress) != len(items) {
+		t.Fatalf("got %d progress reports, want %d", len(progress), len(items))
+	}
+	last := progress[len(progress)-1]
+	if last.Completed != 8 || last.Failed != 2 || last.Total != 8 {
+		t.Errorf("last progress = %+v, want 8 completed with 2 failed", last)
+	}
+}
+
+func TestRun_StopOnError(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+	var calls int32
+	errBoom := errors.New("boom")
+	results, err := Run(context.Background(), items, func(ctx context.Context, n int) (struct{}, error) {
+		atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			return struct{}{}, errBoom
+		}
+		return struct{}{}, nil
+	}, Options{Concurrency: 2, StopOnError: true})
+
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Run() error = %v, want %v", err, errBoom)
+	}
+	skipped := 0
+	for _, r := range results {
+		if r.Skipped {
+			skipped++
+		}
+	}
+	if calls >= int32(len(items)) || skipped != len(items)-int(calls) {
+		t.Errorf("%d operations ran and %d were skipped, want the batch to stop", calls, skipped)
+	}
+}
+
+func TestRun_StopOnError_sequential(t *testing.T) {
+	errBoom := errors.New("boom")
+	var ran []int
+	var mu sync.Mutex
+	results, _ := Run(context.Background(), []int{0, 1, 2}, func(ctx context.Context, n int) (struct{}, error) {
+		mu.Lock()
+		ran = append(ran, n)
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		if n == 0 {
+			return struct{}{}, errBoom
+		}
+		return struct{}{}, nil
+	}, Options{Concurrency: 1, StopOnError: true})
+
+	if len(ran) != 1 {
+		t.Errorf("items %v ran, want only [0]", ran)
+	}
+	if !results[1].Skipped || !results[2].Skipped {
+		t.Errorf("results = %+v, want items 1 and 2 skipped", results)
+	}
+}
+
+func TestEach_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var once sync.Once
+	err := Each(ctx, []string{"a", "b", "c", "d"}, func(ctx context.Context, s string) error {
+		once.Do(cancel)
+		return nil
+	}, Options{Concurrency: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Each() error = %v, want context.Canceled", err)
+	}
+	if err := Each(context.Background(), nil, func(context.Context, int) error { return nil }, Options{}); err != nil {
+		t.Errorf("Each() on no items returned %v", err)
+	}
+}
+
+func TestErrors_truncated(t *testing.T) {
+	var errs Errors
+	for i := range 12 {
+		errs = append(errs, &ItemError{Index: i, Item: i, Err: errors.New("failed")})
+	}
+	if msg := errs.Error(); !strings.HasPrefix(msg, "12 item(s) failed: ") || !strings.HasSuffix(msg, "; and 2 more") {
+		t.Errorf("Error() = %q", msg)
+	}
+}
+
+func TestEach_suspendServers(t *testing.T) {
+	var mu sync.Mutex
+	suspended := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/application/servers/"), "/suspend")
+		if id == "3" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"code":"NotFoundHttpException","status":"404","detail":"not found"}]}`)
+			return
+		}
+		mu.Lock()
+		suspended[id] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c, err := pterodactyl.New(server.URL, pterodactyl.WithAPIKey("ptla_test"))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	err = Each(context.Background(), []int{1, 2, 3, 4}, func(ctx context.Context, id int) error {
+		return c.Application().SuspendServer(ctx, id)
+	}, Options{Concurrency: 2})
+
+	if !errors.Is(err, pterodactyl.ErrNotFound) {
+		t.Errorf("Each() error = %v, want it to match ErrNotFound", err)
+	}
+	if len(suspended) != 3 {
+		t.Errorf("suspended %v, want servers 1, 2 and 4", suspended)
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/idanyas/go-pterodactyl/application"
 	"github.com/idanyas/go-pterodactyl/client"
@@ -22,6 +24,10 @@ const (
 	APIVersion = "v1"
 	// defaultUserAgent is the default User-Agent header sent with requests.
 	defaultUserAgent = "go-pterodactyl/v1.0"
+
+	// Key prefixes identifying the API an API key belongs to.
+	applicationKeyPrefix = "ptla_"
+	clientKeyPrefix      = "ptlc_"
 )
 
 // ListOptions specifies optional parameters to list methods.
@@ -33,7 +39,20 @@ type ListOptions = pagination.ListOptions
 type Client struct {
 	baseURL    *url.URL
 	apiKey     string
+	appKey     string
+	clientKey  string
+	userAgent  string
 	httpClient *http.Client
+	logger     *slog.Logger
+
+	// Credential providers overriding the keys above, nil if unset
+	appCreds    transport.CredentialProvider
+	clientCreds transport.CredentialProvider
+
+	// Options passed to the transport
+	transportOpts []transport.TransportOption
+	limiter       *transport.RateLimiter
+	transport     *transport.Transport
 
 	// API Clients
 	app    application.ApplicationClient
@@ -44,14 +63,56 @@ type Client struct {
 type Option func(*Client)
 
 // WithAPIKey sets the API key to be used for authentication.
-// The key should be prefixed with `ptla_` for the Application API
-// or `ptlc_` for the Client API.
+// A key prefixed with `ptla_` is only sent to the Application API and a key
+// prefixed with `ptlc_` only to the Client API; requests to the other API fail
+// with transport.ErrNoAPIKey. A key without either prefix is sent to both.
+// WithApplicationKey and WithClientKey take precedence for their API.
 func WithAPIKey(key string) Option {
 	return func(c *Client) {
 		c.apiKey = key
 	}
 }
 
+// WithApplicationKey sets the key used for the Application API. New returns an
+// error if the key has the `ptlc_` prefix of a Client API key.
+func WithApplicationKey(key string) Option {
+	return func(c *Client) {
+		c.appKey = key
+	}
+}
+
+// WithClientKey sets the key used for the Client API. New returns an error if
+// the key has the `ptla_` prefix of an Application API key.
+func WithClientKey(key string) Option {
+	return func(c *Client) {
+		c.clientKey = key
+	}
+}
+
+// WithApplicationCredentials obtains the Application API key from p on every
+// request instead of using a fixed key, for example to pick up a rotated key
+// from transport.NewFileCredentials.
+func WithApplicationCredentials(p transport.CredentialProvider) Option {
+	return func(c *Client) {
+		c.appCreds = p
+	}
+}
+
+// WithClientCredentials obtains the Client API key from p on every request
+// instead of using a fixed key.
+//
+// Without it, a key set on the request context with transport.ContextWithAPIKey
+// takes precedence over the Client API key, so one Client can act for
+// several users:
+//
+//	ctx = transport.ContextWithAPIKey(ctx, customerKey)
+//	err := client.Client().SendPowerAction(ctx, id, "restart")
+func WithClientCredentials(p transport.CredentialProvider) Option {
+	return func(c *Client) {
+		c.clientCreds = p
+	}
+}
+
 // WithHTTPClient sets a custom http.Client for the Pterodactyl client.
 // This is useful for configuring custom transports, timeouts, or other settings.
 func WithHTTPClient(httpClient *http.Client) Option {
@@ -60,6 +121,106 @@ func WithHTTPClient(httpClient *http.Client) Option {
 	}
 }
 
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		if ua != "" {
+			c.userAgent = ua
+		}
+	}
+}
+
+// WithMaxRetries sets the maximum number of attempts for a request that fails
+// with a network error, a 5xx response or a 429 response. A value of 1 disables
+// retries.
+func WithMaxRetries(max int) Option {
+	return WithTransportOptions(transport.WithMaxRetries(max))
+}
+
+// WithRetryWait sets the minimum and maximum backoff between retries.
+func WithRetryWait(min, max time.Duration) Option {
+	return WithTransportOptions(transport.WithRetryWaitMin(min), transport.WithRetryWaitMax(max))
+}
+
+// WithRateLimitMaxWait sets the maximum time to wait for a rate limit reset
+// before retrying a 429 response.
+func WithRateLimitMaxWait(d time.Duration) Option {
+	return WithTransportOptions(transport.WithRateLimitMaxWait(d))
+}
+
+// WithRetryBudget caps the total time this Client spends waiting between
+// retries to max within any window of the given length. Once the budget is
+// spent, failed requests return their last response or error immediately.
+func WithRetryBudget(max, window time.Duration) Option {
+	return WithTransportOptions(transport.WithRetryBudget(transport.NewRetryBudget(max, window)))
+}
+
+// WithCircuitBreaker stops requests to the panel with a circuit breaker after
+// repeated failures, so they fail fast with transport.ErrCircuitOpen instead of
+// waiting through retries while the panel is down.
+func WithCircuitBreaker(b *transport.CircuitBreaker) Option {
+	return WithTransportOptions(transport.WithCircuitBreaker(b))
+}
+
+// WithCache serves repeated reads from c, for example:
+//
+//	cache := transport.NewCache(
+//		transport.WithCacheTTL("client/servers/{server}", 30*time.Second),
+//		transport.WithCacheTTL("client/permissions", time.Hour),
+//	)
+//	c, err := pterodactyl.New(panelURL, pterodactyl.WithAPIKey(key), pterodactyl.WithCache(cache))
+//
+// Use transport.NoCache through transport.WithRequestOptions to skip it for a
+// call.
+func WithCache(c *transport.Cache) Option {
+	return WithTransportOptions(transport.WithCache(c))
+}
+
+// WithRateLimiter sets the limiter that paces requests ahead of the panel's
+// rate limit. The limiter tracks each API key and API separately. By default
+// each Client has its own limiter; pass a shared one to pace several clients
+// of the same panel together, or nil to disable pacing.
+func WithRateLimiter(l *transport.RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+// WithCoalescing sets whether identical GET requests in flight at the same
+// time are merged into one, which is the default. Each caller still decodes
+// its own copy of the response. Use transport.NoCoalescing through
+// transport.WithRequestOptions to opt out for a call.
+func WithCoalescing(enabled bool) Option {
+	return WithTransportOptions(transport.WithCoalescing(enabled))
+}
+
+// WithLogger logs requests, retries and WebSocket connection events to l, with
+// credentials redacted. Requests are logged at debug level and retries at warn
+// level; use transport.WithLogLevels through WithTransportOptions to change
+// them.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+		c.transportOpts = append(c.transportOpts, transport.WithLogger(l))
+	}
+}
+
+// WithHooks calls h around every request, for example to trace and measure
+// requests with OpenTelemetry. See transport.Hooks.
+func WithHooks(h transport.Hooks) Option {
+	return WithTransportOptions(transport.WithHooks(h))
+}
+
+// WithTransportOptions passes options to the underlying transport.
+//
+// Settings can also be overridden for a single call through its context, see
+// transport.WithRequestOptions.
+func WithTransportOptions(opts ...transport.TransportOption) Option {
+	return func(c *Client) {
+		c.transportOpts = append(c.transportOpts, opts...)
+	}
+}
+
 // New creates a new Pterodactyl API client.
 //
 // panelURL is the base URL of the Pterodactyl panel (e.g., "https://panel.example.com").
@@ -75,13 +236,38 @@ func New(panelURL string, opts ...Option) (*Client, error) {
 	}
 
 	c := &Client{
-		baseURL: baseURL,
+		baseURL:   baseURL,
+		userAgent: defaultUserAgent,
+		limiter:   transport.NewRateLimiter(),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if strings.HasPrefix(c.appKey, clientKeyPrefix) {
+		return nil, fmt.Errorf("application API key must not be a client API key (%s prefix)", clientKeyPrefix)
+	}
+	if strings.HasPrefix(c.clientKey, applicationKeyPrefix) {
+		return nil, fmt.Errorf("client API key must not be an application API key (%s prefix)", applicationKeyPrefix)
+	}
+
+	// A key set with WithAPIKey only goes to the API its prefix belongs to;
+	// the other API then fails with transport.ErrNoAPIKey.
+	appFallback, clientFallback := c.apiKey, c.apiKey
+	switch {
+	case strings.HasPrefix(c.apiKey, applicationKeyPrefix):
+		clientFallback = ""
+	case strings.HasPrefix(c.apiKey, clientKeyPrefix):
+		appFallback = ""
+	}
+	if c.appCreds == nil {
+		c.appCreds = transport.StaticCredentials(firstNonEmpty(c.appKey, appFallback))
+	}
+	if c.clientCreds == nil {
+		c.clientCreds = transport.ContextCredentials(transport.StaticCredentials(firstNonEmpty(c.clientKey, clientFallback)))
+	}
+
 	if c.httpClient == nil {
 		c.httpClient = &http.Client{}
 	}
@@ -91,12 +277,19 @@ func New(panelURL string, opts ...Option) (*Client, error) {
 	if baseTransport == nil {
 		baseTransport = http.DefaultTransport
 	}
-	c.httpClient.Transport = transport.New(
+	c.transport = transport.New(
 		baseTransport,
 		c.apiKey,
 		APIVersion,
-		defaultUserAgent,
+		c.userAgent,
+		append([]transport.TransportOption{
+			transport.WithRateLimiter(c.limiter),
+			transport.WithCoalescing(true),
+			transport.WithPathCredentials(baseURL.Path+"application/", c.appCreds),
+			transport.WithPathCredentials(baseURL.Path+"client/", c.clientCreds),
+		}, c.transportOpts...)...,
 	)
+	c.httpClient.Transport = c.transport
 
 	c.app = application.New(c)
 	c.client = client.New(c)
@@ -104,11 +297,25 @@ func New(panelURL string, opts ...Option) (*Client, error) {
 	return c, nil
 }
 
+// Logger returns the logger set with WithLogger, or nil.
+func (c *Client) Logger() *slog.Logger {
+	return c.logger
+}
+
 // Application returns a client for interacting with the Application API.
 func (c *Client) Application() application.ApplicationClient {
 	return c.app
 }
 
+// RateLimitState returns the current estimate of the panel's rate limit for
+// requests to path, such as "application/users" or "client", made with ctx,
+// as learned from the X-RateLimit headers of previous responses. The panel
+// limits each API key and API separately. It returns the zero value when rate
+// limiting is disabled or no response has been seen.
+func (c *Client) RateLimitState(ctx context.Context, path string) transport.RateLimitInfo {
+	return c.transport.RateLimitState(ctx, c.baseURL.ResolveReference(&url.URL{Path: path}).Path)
+}
+
 // Client returns a client for interacting with the Client API.
 func (c *Client) Client() client.ClientClient {
 	return c.client
@@ -185,3 +392,12 @@ func (c *Client) Do(ctx context.Context, method, path string, body, v interface{
 func (c *Client) DoRequest(req *http.Request, v interface{}) (*http.Response, error) {
 	return c.do(req, v)
 }
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
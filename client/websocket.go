@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 
 	"github.com/idanyas/go-pterodactyl/websocket"
@@ -32,5 +33,14 @@ func (c *client) ConnectWebSocketWithReconnect(ctx context.Context, serverID str
 		return nil, fmt.Errorf("failed to get websocket credentials: %w", err)
 	}
 
-	return websocket.NewConn(ctx, response.Data.Socket, response.Data.Token, reconnectOpts)
+	var opts []websocket.ConnOption
+	if l, ok := c.client.(logger); ok && l.Logger() != nil {
+		opts = append(opts, websocket.WithLogger(l.Logger()))
+	}
+	return websocket.NewConn(ctx, response.Data.Socket, response.Data.Token, reconnectOpts, opts...)
+}
+
+// logger is implemented by API clients that log WebSocket connections.
+type logger interface {
+	Logger() *slog.Logger
 }
@@ -1,14 +1,24 @@
 package pterodactyl
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/transport"
 )
 
 // setup sets up a test HTTP server along with a Client that is
@@ -167,4 +177,386 @@ func TestClient_Do_withBody(t *testing.T) {
 	if !respBody.Success {
 		t.Error("response body success is false, want true")
 	}
-}
\ No newline at end of file
+}
+func TestNew_transportOptions(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+
+	var requests int
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		testHeader(t, r, "User-Agent", "my-app/2.0")
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	client, err := New(serverURL,
+		WithAPIKey("test-key"),
+		WithUserAgent("my-app/2.0"),
+		WithMaxRetries(2),
+		WithRetryWait(time.Millisecond, time.Millisecond),
+		WithRateLimitMaxWait(time.Second),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	_, err = client.Do(context.Background(), http.MethodGet, "", nil, nil)
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Do() error = %v, want a 502 APIError", err)
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2", requests)
+	}
+
+	requests = 0
+	ctx := transport.WithRequestOptions(context.Background(), transport.NoRetries())
+	client.Do(ctx, http.MethodGet, "", nil, nil)
+	if requests != 1 {
+		t.Errorf("got %d requests with NoRetries, want 1", requests)
+	}
+}
+
+func TestClient_RateLimitState(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "240")
+		w.Header().Set("X-RateLimit-Remaining", "200")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := testClient(t, serverURL)
+	if got := client.RateLimitState(context.Background(), ""); got.Limit != 0 {
+		t.Errorf("RateLimitState() before any request = %+v, want zero", got)
+	}
+	if _, err := client.Do(context.Background(), http.MethodGet, "", nil, nil); err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	if got := client.RateLimitState(context.Background(), ""); got.Limit != 240 || got.Remaining < 200 {
+		t.Errorf("RateLimitState() = %+v, want limit 240 and at least 200 remaining", got)
+	}
+
+	unlimited, err := New(serverURL, WithAPIKey("test-key"), WithRateLimiter(nil))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	unlimited.Do(context.Background(), http.MethodGet, "", nil, nil)
+	if got := unlimited.RateLimitState(context.Background(), ""); got.Limit != 0 {
+		t.Errorf("RateLimitState() without a limiter = %+v, want zero", got)
+	}
+}
+
+func TestClient_RateLimitState_perAPI(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/application/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "240")
+		w.Header().Set("X-RateLimit-Remaining", "100")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/client", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "720")
+		w.Header().Set("X-RateLimit-Remaining", "700")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := testClient(t, serverURL)
+	ctx := context.Background()
+	client.Do(ctx, http.MethodGet, "application/users", nil, nil)
+	client.Do(ctx, http.MethodGet, "client", nil, nil)
+
+	if got := client.RateLimitState(ctx, "application/users"); got.Limit != 240 {
+		t.Errorf("Application RateLimitState() = %+v, want limit 240", got)
+	}
+	if got := client.RateLimitState(ctx, "client"); got.Limit != 720 {
+		t.Errorf("Client RateLimitState() = %+v, want limit 720", got)
+	}
+}
+
+func TestNew_applicationAndClientKeys(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/application/users", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "Authorization", "Bearer ptla_app")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/client/account", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "Authorization", "Bearer ptlc_client")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client, err := New(serverURL, WithApplicationKey("ptla_app"), WithClientKey("ptlc_client"))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := client.Do(ctx, http.MethodGet, "application/users", nil, nil); err != nil {
+		t.Errorf("Do(application) returned error: %v", err)
+	}
+	if _, err := client.Do(ctx, http.MethodGet, "client/account", nil, nil); err != nil {
+		t.Errorf("Do(client) returned error: %v", err)
+	}
+
+	appOnly, err := New(serverURL, WithApplicationKey("ptla_app"))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := appOnly.Do(ctx, http.MethodGet, "client/account", nil, nil); !errors.Is(err, transport.ErrNoAPIKey) {
+		t.Errorf("Do(client) without a client key: expected ErrNoAPIKey, got %v", err)
+	}
+}
+
+func TestNew_keyPrefixes(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+		ok   bool
+	}{
+		{"application key", WithApplicationKey("ptla_key"), true},
+		{"legacy application key", WithApplicationKey("legacykey"), true},
+		{"client key as application key", WithApplicationKey("ptlc_key"), false},
+		{"client key", WithClientKey("ptlc_key"), true},
+		{"application key as client key", WithClientKey("ptla_key"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New("https://panel.example.com", tt.opt)
+			if (err == nil) != tt.ok {
+				t.Errorf("New() error = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNew_apiKeyRoutedByPrefix(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		key            string
+		app, clientAPI bool
+	}{
+		{"ptla_key", true, false},
+		{"ptlc_key", false, true},
+		{"legacykey", true, true},
+	}
+	for _, tt := range tests {
+		client, err := New(serverURL, WithAPIKey(tt.key))
+		if err != nil {
+			t.Fatalf("New() failed: %v", err)
+		}
+		ctx := context.Background()
+		for _, call := range []struct {
+			path string
+			ok   bool
+		}{{"application/users", tt.app}, {"client/account", tt.clientAPI}} {
+			_, err := client.Do(ctx, http.MethodGet, call.path, nil, nil)
+			if call.ok && err != nil {
+				t.Errorf("%s: GET %s returned error: %v", tt.key, call.path, err)
+			}
+			if !call.ok && !errors.Is(err, transport.ErrNoAPIKey) {
+				t.Errorf("%s: GET %s error = %v, want ErrNoAPIKey", tt.key, call.path, err)
+			}
+		}
+	}
+}
+
+func TestNew_contextClientKey(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+
+	var got []string
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Path+" "+r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client, err := New(serverURL, WithApplicationKey("ptla_admin"))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := transport.ContextWithAPIKey(context.Background(), "ptlc_customer")
+	if _, err := client.Do(ctx, http.MethodGet, "client/account", nil, nil); err != nil {
+		t.Errorf("Do(client) returned error: %v", err)
+	}
+	if _, err := client.Do(ctx, http.MethodGet, "application/users", nil, nil); err != nil {
+		t.Errorf("Do(application) returned error: %v", err)
+	}
+
+	want := []string{
+		"/api/client/account Bearer ptlc_customer",
+		"/api/application/users Bearer ptla_admin",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestNew_logger(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/api/client/account", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := New(serverURL, WithAPIKey("test-key"), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if client.Logger() != logger {
+		t.Error("Logger() did not return the configured logger")
+	}
+	if _, err := client.Do(context.Background(), http.MethodGet, "client/account", nil, nil); err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	if !strings.Contains(logs.String(), "path=/api/client/account") || !strings.Contains(logs.String(), "status=204") {
+		t.Errorf("request was not logged:\n%s", logs.String())
+	}
+}
+
+func TestNew_hooks(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/api/application/servers/7/suspend", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	var info transport.RequestInfo
+	var result transport.Result
+	client, err := New(serverURL, WithAPIKey("test-key"), WithHooks(transport.Hooks{
+		AfterResponse: func(ctx context.Context, i transport.RequestInfo, r transport.Result) {
+			info, result = i, r
+		},
+	}))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := client.Application().SuspendServer(context.Background(), 7); err != nil {
+		t.Fatalf("SuspendServer() returned error: %v", err)
+	}
+	if info.Operation != "application.SuspendServer" || info.ServerID != "7" {
+		t.Errorf("hook got %+v, want application.SuspendServer on server 7", info)
+	}
+	if result.Attempts != 1 || result.Response.StatusCode != http.StatusNoContent {
+		t.Errorf("hook got result %+v, want one attempt ending in 204", result)
+	}
+}
+
+func TestCaptureResponse(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/api/client/servers/abc123", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "240")
+		w.Header().Set("X-RateLimit-Remaining", "100")
+		fmt.Fprint(w, `{"object":"server","attributes":{"identifier":"abc123","name":"Test"}}`)
+	})
+
+	client := testClient(t, serverURL)
+	var meta transport.ResponseMeta
+	ctx := transport.WithRequestOptions(context.Background(), transport.CaptureResponse(&meta))
+	server, err := client.Client().GetServer(ctx, "abc123")
+	if err != nil {
+		t.Fatalf("GetServer() returned error: %v", err)
+	}
+	if server.Identifier != "abc123" {
+		t.Errorf("GetServer() identifier = %q, want abc123", server.Identifier)
+	}
+	if meta.StatusCode != http.StatusOK || meta.RateLimit.Remaining != 100 || meta.Attempts != 1 {
+		t.Errorf("meta = %+v, want status 200 with 100 requests remaining", meta)
+	}
+}
+
+func TestNew_cache(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+	var requests int
+	mux.HandleFunc("/api/client/servers/abc123/startup", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"object":"list","data":[],"meta":{"startup_command":"java","docker_images":{}}}`)
+	})
+	mux.HandleFunc("/api/client/servers/abc123/startup/variable", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"object":"egg_variable","attributes":{"env_variable":"VERSION","server_value":"2"}}`)
+	})
+
+	cache := transport.NewCache(transport.WithCacheTTL("client/servers/{server}/startup", time.Minute))
+	client, err := New(serverURL, WithAPIKey("test-key"), WithCache(cache))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := client.Client().GetStartupConfig(ctx, "abc123"); err != nil {
+			t.Fatalf("GetStartupConfig() returned error: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+
+	if _, err := client.Client().UpdateStartupVariable(ctx, "abc123", "VERSION", "2"); err != nil {
+		t.Fatalf("UpdateStartupVariable() returned error: %v", err)
+	}
+	if _, err := client.Client().GetStartupConfig(ctx, "abc123"); err != nil {
+		t.Fatalf("GetStartupConfig() returned error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests after the update, got %d", requests)
+	}
+}
+
+func TestNew_coalescing(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		mux, serverURL, teardown := setup()
+		var requests int32
+		release := make(chan struct{})
+		mux.HandleFunc("/api/client/servers/abc123", func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			<-release
+			fmt.Fprint(w, `{"object":"server","attributes":{"identifier":"abc123","name":"Test"}}`)
+		})
+
+		client, err := New(serverURL, WithAPIKey("test-key"), WithCoalescing(enabled))
+		if err != nil {
+			t.Fatalf("New() failed: %v", err)
+		}
+		const callers = 5
+		servers := make([]*models.Server, callers)
+		var wg sync.WaitGroup
+		for i := range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				server, err := client.Client().GetServer(context.Background(), "abc123")
+				if err != nil {
+					t.Errorf("GetServer() returned error: %v", err)
+					return
+				}
+				servers[i] = server
+			}()
+		}
+		// Give every caller time to send or join the request.
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		teardown()
+
+		want := int32(1)
+		if !enabled {
+			want = callers
+		}
+		if requests != want {
+			t.Errorf("coalescing %v: expected %d requests, got %d", enabled, want, requests)
+		}
+		if enabled && servers[0] != nil && servers[0] == servers[1] {
+			t.Error("callers share a decoded server")
+		}
+	}
+}
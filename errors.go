@@ -2,14 +2,62 @@ package pterodactyl
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 
+	"github.com/idanyas/go-pterodactyl/internal/redact"
 	"github.com/idanyas/go-pterodactyl/transport"
+	"github.com/idanyas/go-pterodactyl/validation"
 )
 
+// Sentinel errors matched by APIError with errors.Is, for example:
+//
+//	if errors.Is(err, pterodactyl.ErrNotFound) {
+//	    // the server does not exist
+//	}
+var (
+	// ErrUnauthorized matches 401 responses, usually a missing or revoked API key.
+	ErrUnauthorized = errors.New("pterodactyl: unauthorized")
+	// ErrForbidden matches 403 responses, e.g. an API key without the required
+	// permission.
+	ErrForbidden = errors.New("pterodactyl: forbidden")
+	// ErrNotFound matches 404 responses.
+	ErrNotFound = errors.New("pterodactyl: not found")
+	// ErrConflict matches 409 responses, including ErrServerSuspended and
+	// ErrServerInstalling.
+	ErrConflict = errors.New("pterodactyl: conflict")
+	// ErrValidation matches responses rejecting the request data. The invalid
+	// fields are available from APIError.FieldErrors.
+	ErrValidation = errors.New("pterodactyl: validation failed")
+	// ErrServerSuspended matches requests rejected because the server is suspended.
+	ErrServerSuspended = errors.New("pterodactyl: server is suspended")
+	// ErrServerInstalling matches requests rejected because the server has not
+	// finished installing.
+	ErrServerInstalling = errors.New("pterodactyl: server is installing")
+	// ErrTooManyBackups matches backup creation rejected by the server's backup
+	// limit.
+	ErrTooManyBackups = errors.New("pterodactyl: backup limit reached")
+	// ErrRateLimited matches 429 responses and transport.RateLimitedError.
+	ErrRateLimited = transport.ErrRateLimited
+)
+
+// Error codes returned by the panel.
+const (
+	codeValidation          = "ValidationException"
+	codeServerStateConflict = "ServerStateConflictException"
+	codeTooManyBackups      = "TooManyBackupsException"
+)
+
+// ErrorMeta holds additional details of an error. The panel sets it for
+// validation errors.
+type ErrorMeta struct {
+	SourceField string `json:"source_field"`
+	Rule        string `json:"rule"`
+}
+
 // ErrorSource provides an optional object pointing to the specific field that caused the error.
 type ErrorSource struct {
 	Field string `json:"field"`
@@ -21,6 +69,18 @@ type ErrorDetail struct {
 	Status string       `json:"status"`
 	Detail string       `json:"detail"`
 	Source *ErrorSource `json:"source,omitempty"`
+	Meta   *ErrorMeta   `json:"meta,omitempty"`
+}
+
+// field returns the request field the error refers to, if any.
+func (d ErrorDetail) field() string {
+	if d.Source != nil && d.Source.Field != "" {
+		return d.Source.Field
+	}
+	if d.Meta != nil {
+		return d.Meta.SourceField
+	}
+	return ""
 }
 
 // errorResponse is the structure of an error response from the Pterodactyl API.
@@ -38,9 +98,9 @@ type APIError struct {
 	Errors []ErrorDetail
 	// Method is the HTTP method that was used.
 	Method string
-	// URL is the URL that was requested (with sensitive info redacted).
+	// URL is the URL that was requested, with credentials redacted.
 	URL string
-	// raw is the raw response body, for debugging.
+	// raw is the response body with credentials redacted, for debugging.
 	raw []byte
 }
 
@@ -50,7 +110,7 @@ func (e *APIError) Error() string {
 
 	// Add method and URL
 	if e.Method != "" && e.URL != "" {
-		parts = append(parts, fmt.Sprintf("%s %s", e.Method, redactURL(e.URL)))
+		parts = append(parts, fmt.Sprintf("%s %s", e.Method, redact.URL(e.URL)))
 	}
 
 	// Add status code
@@ -61,22 +121,91 @@ func (e *APIError) Error() string {
 		var errorMsgs []string
 		for _, err := range e.Errors {
 			msg := err.Detail
-			if err.Source != nil && err.Source.Field != "" {
-				msg = fmt.Sprintf("%s (field: %s)", msg, err.Source.Field)
+			if field := err.field(); field != "" {
+				msg = fmt.Sprintf("%s (field: %s)", msg, field)
 			}
 			errorMsgs = append(errorMsgs, msg)
 		}
 		parts = append(parts, strings.Join(errorMsgs, "; "))
 	}
 
-	return fmt.Sprintf("pterodactyl: %s", strings.Join(parts, ": "))
+	return redact.String(fmt.Sprintf("pterodactyl: %s", strings.Join(parts, ": ")))
 }
 
-// redactURL removes sensitive information from URLs for error messages.
-func redactURL(rawURL string) string {
-	// Redact any tokens or sensitive query parameters
-	// For now, just return as-is since our URLs don't contain sensitive data in path
-	return rawURL
+// Is reports whether the error matches one of the sentinel errors of this
+// package, based on the status code and the error codes in the response.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity || e.hasCode(codeValidation)
+	case ErrServerSuspended:
+		return e.hasConflict("suspended")
+	case ErrServerInstalling:
+		return e.hasConflict("installation")
+	case ErrTooManyBackups:
+		return e.hasCode(codeTooManyBackups)
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// As sets target to the validation errors of the response if target is a
+// **validation.ValidationError, so server-side validation failures can be
+// handled like those found by validation.Validate.
+func (e *APIError) As(target any) bool {
+	v, ok := target.(**validation.ValidationError)
+	if !ok || !e.Is(ErrValidation) {
+		return false
+	}
+	*v = &validation.ValidationError{Errors: e.FieldErrors()}
+	return true
+}
+
+// FieldErrors returns the per-field errors of a validation failure.
+func (e *APIError) FieldErrors() []validation.FieldError {
+	var fields []validation.FieldError
+	for _, d := range e.Errors {
+		field := d.field()
+		if field == "" {
+			continue
+		}
+		fe := validation.FieldError{Field: field, Message: d.Detail}
+		if d.Meta != nil {
+			fe.Tag = d.Meta.Rule
+		}
+		fields = append(fields, fe)
+	}
+	return fields
+}
+
+func (e *APIError) hasCode(code string) bool {
+	for _, d := range e.Errors {
+		if d.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// hasConflict reports whether the panel refused the request because of the
+// server's state, with a detail message mentioning word. The panel does not
+// distinguish the states by code.
+func (e *APIError) hasConflict(word string) bool {
+	for _, d := range e.Errors {
+		if d.Code == codeServerStateConflict && strings.Contains(strings.ToLower(d.Detail), word) {
+			return true
+		}
+	}
+	return false
 }
 
 // CheckResponse checks the API response for errors, and returns one if present.
@@ -90,12 +219,12 @@ func CheckResponse(r *http.Response) error {
 		StatusCode: r.StatusCode,
 		RateLimit:  transport.ParseRateLimit(r),
 		Method:     r.Request.Method,
-		URL:        r.Request.URL.String(),
+		URL:        redact.URL(r.Request.URL.String()),
 	}
 
 	data, err := io.ReadAll(r.Body)
 	if err == nil && len(data) > 0 {
-		apiErr.raw = data
+		apiErr.raw = redact.Bytes(data)
 		errResponse := &errorResponse{}
 		if err := json.Unmarshal(data, errResponse); err == nil {
 			apiErr.Errors = errResponse.Errors
@@ -2,11 +2,19 @@ package pterodactyl
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/idanyas/go-pterodactyl/pagination"
+	"github.com/idanyas/go-pterodactyl/transport"
+	"github.com/idanyas/go-pterodactyl/validation"
 )
 
 func TestCheckResponse_Success(t *testing.T) {
@@ -133,6 +141,109 @@ func TestAPIError_Error(t *testing.T) {
 	}
 }
 
+func TestAPIError_Is(t *testing.T) {
+	conflict := func(detail string) *APIError {
+		return &APIError{StatusCode: 409, Errors: []ErrorDetail{{Code: "ServerStateConflictException", Detail: detail}}}
+	}
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"not found", &APIError{StatusCode: 404}, ErrNotFound, true},
+		{"not found is not forbidden", &APIError{StatusCode: 404}, ErrForbidden, false},
+		{"unauthorized", &APIError{StatusCode: 401}, ErrUnauthorized, true},
+		{"forbidden", &APIError{StatusCode: 403}, ErrForbidden, true},
+		{"validation status", &APIError{StatusCode: 422}, ErrValidation, true},
+		{"validation code", &APIError{StatusCode: 400, Errors: []ErrorDetail{{Code: "ValidationException"}}}, ErrValidation, true},
+		{"suspended", conflict("This server is currently suspended and the functionality requested is unavailable."), ErrServerSuspended, true},
+		{"suspended is a conflict", conflict("This server is currently suspended."), ErrConflict, true},
+		{"suspended is not installing", conflict("This server is currently suspended."), ErrServerInstalling, false},
+		{"installing", conflict("This server has not yet completed its installation process, please try again later."), ErrServerInstalling, true},
+		{"too many backups", &APIError{StatusCode: 400, Errors: []ErrorDetail{{Code: "TooManyBackupsException"}}}, ErrTooManyBackups, true},
+		{"rate limited", &APIError{StatusCode: 429}, ErrRateLimited, true},
+		{"rate limited in transport", &transport.RateLimitedError{}, ErrRateLimited, true},
+		{"wrapped", fmt.Errorf("failed to fetch page 2: %w", &APIError{StatusCode: 404}), ErrNotFound, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIError_FieldErrors(t *testing.T) {
+	body := `{"errors": [
+		{"code": "ValidationException", "status": "422", "detail": "The email field is required.", "meta": {"source_field": "email", "rule": "required"}},
+		{"code": "ValidationException", "status": "422", "detail": "The username has already been taken.", "source": {"field": "username"}}
+	]}`
+	resp := &http.Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    &http.Request{Method: "POST", URL: mustParseURL("https://panel.example.com/api/application/users")},
+	}
+	err := fmt.Errorf("failed to create user: %w", CheckResponse(resp))
+
+	want := []validation.FieldError{
+		{Field: "email", Tag: "required", Message: "The email field is required."},
+		{Field: "username", Message: "The username has already been taken."},
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if got := apiErr.FieldErrors(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldErrors() = %+v, want %+v", got, want)
+	}
+
+	var valErr *validation.ValidationError
+	if !errors.As(err, &valErr) {
+		t.Fatal("expected errors.As to match *validation.ValidationError")
+	}
+	if !reflect.DeepEqual(valErr.Errors, want) {
+		t.Errorf("ValidationError.Errors = %+v, want %+v", valErr.Errors, want)
+	}
+
+	if errors.As(&APIError{StatusCode: 404}, &valErr) {
+		t.Error("errors.As matched *validation.ValidationError for a 404")
+	}
+}
+
+func TestAPIError_IsThroughPagination(t *testing.T) {
+	mux, serverURL, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/api/application/servers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"errors": [{"code": "AccessDeniedHttpException", "status": "403", "detail": "This action is unauthorized."}]}`)
+	})
+
+	client := testClient(t, serverURL)
+	_, _, err := client.Application().ListServers(context.Background(), pagination.ListOptions{})
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestCheckResponse_Redacted(t *testing.T) {
+	body := `{"errors": [{"code": "HttpException", "status": "500", "detail": "Request to https://node:8080/download?token=secret-jwt failed", "meta": {"password": "db-password"}}]}`
+	resp := &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    &http.Request{Method: "GET", URL: mustParseURL("https://panel.example.com/download?token=url-token")},
+	}
+	apiErr := CheckResponse(resp).(*APIError)
+	for _, out := range []string{apiErr.Error(), apiErr.URL, string(apiErr.raw)} {
+		for _, secret := range []string{"secret-jwt", "db-password", "url-token"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("%q contains %q", out, secret)
+			}
+		}
+	}
+}
+
 func mustParseURL(rawURL string) *url.URL {
 	u, err := url.Parse(rawURL)
 	if err != nil {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -34,20 +35,17 @@ func main() {
 	// Example 1: Wait for server to reach running state
 	fmt.Println("=== Waiting for Server to Start ===")
 
-	// Start the server
-	err = clientAPI.SendPowerAction(ctx, serverID, "start")
-	if err != nil {
-		log.Printf("Failed to start server: %v", err)
-	}
-
-	// Wait for it to reach running state
+	// Start the server and wait for it to reach the running state.
+	// Status events from the WebSocket are used when available.
 	waiter := helpers.NewStateWaiter(clientAPI)
 	waitCtx, waitCancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer waitCancel()
 
-	err = waiter.WaitForState(waitCtx, serverID, "running", 5*time.Second)
-	if err != nil {
-		log.Printf("Failed to wait for server: %v", err)
+	err = waiter.StartAndWait(waitCtx, serverID, helpers.WaitOptions{PollInterval: 5 * time.Second})
+	if errors.Is(err, helpers.ErrServerCrashed) {
+		log.Printf("Server crashed while starting")
+	} else if err != nil {
+		log.Printf("Failed to start server: %v", err)
 	} else {
 		fmt.Println("✓ Server is now running!")
 	}
@@ -23,26 +23,17 @@ func NewStateWaiter(c client.ClientClient) *StateWaiter {
 }
 
 // WaitForState polls the server until it reaches the desired state or the context times out.
+// The current state is checked immediately and then once per pollInterval.
 // Returns an error if the context is cancelled or if polling fails.
+//
+// Use WaitForStates to listen for WebSocket status events instead of polling.
 func (w *StateWaiter) WaitForState(ctx context.Context, serverID string, desiredState string, pollInterval time.Duration) error {
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-ticker.C:
-			resources, err := w.client.GetServerResources(ctx, serverID)
-			if err != nil {
-				return fmt.Errorf("failed to get server resources: %w", err)
-			}
-
-			if resources.CurrentState == desiredState {
-				return nil
-			}
-		}
-	}
+	_, err := w.wait(ctx, serverID, []string{desiredState}, WaitOptions{
+		PollInterval:          pollInterval,
+		DisableWebSocket:      true,
+		DisableCrashDetection: true,
+	}, nil)
+	return err
 }
 
 // FileDownloader provides methods for downloading server files.
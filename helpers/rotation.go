@@ -0,0 +1,225 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/client"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/transport"
+)
+
+const defaultKeyDescription = "go-pterodactyl rotated key"
+
+// ErrNoRollback is returned by KeyRotator.Rollback when there is no previous
+// key to return to, either because no rotation happened yet or because the
+// previous key was already revoked.
+var ErrNoRollback = errors.New("no previous API key to roll back to")
+
+// KeyRotatorOptions configures a KeyRotator.
+type KeyRotatorOptions struct {
+	// Description is given to the keys created by the rotator. Defaults to
+	// "go-pterodactyl rotated key".
+	Description string
+	// AllowedIPs restricts the keys created by the rotator to these addresses.
+	AllowedIPs []string
+	// RevokeAfter keeps the previous key valid for this long after a rotation,
+	// so requests signed with it can finish and the rotation can be rolled
+	// back. Zero revokes it as soon as the new key is verified.
+	RevokeAfter time.Duration
+	// OnRotate is called with the new key after each successful rotation, for
+	// example to persist its secret token.
+	OnRotate func(key *models.APIKey)
+	// OnError is called with the errors of scheduled rotations and revocations
+	// in Run.
+	OnError func(err error)
+}
+
+// KeyRotator replaces the Client API key of an account with a fresh one. A
+// rotation creates a new key with the current one, switches the shared
+// credentials to it, checks that it works, and then revokes the old key.
+// If the new key does not work, the rotation is rolled back automatically.
+//
+// The rotated credentials must be used by the client passed to the rotator:
+//
+//	creds := transport.NewRotatingCredentials(key)
+//	c, _ := pterodactyl.New(panelURL, pterodactyl.WithClientCredentials(creds))
+//	rotator := helpers.NewKeyRotator(c.Client(), creds, helpers.KeyRotatorOptions{})
+type KeyRotator struct {
+	client client.ClientClient
+	creds  *transport.RotatingCredentials
+	opts   KeyRotatorOptions
+
+	mu       sync.Mutex
+	current  string // identifier of the key created by the last rotation
+	previous *previousKey
+}
+
+// previousKey is a replaced key that has not been revoked yet.
+type previousKey struct {
+	token      string
+	identifier string // empty if the key was not found on the account
+}
+
+// NewKeyRotator creates a KeyRotator rotating the key held by creds.
+func NewKeyRotator(c client.ClientClient, creds *transport.RotatingCredentials, opts KeyRotatorOptions) *KeyRotator {
+	if opts.Description == "" {
+		opts.Description = defaultKeyDescription
+	}
+	return &KeyRotator{client: c, creds: creds, opts: opts}
+}
+
+// Rotate switches to a new key and returns it. A previous key still kept for
+// rollback is revoked first. The key being replaced is revoked immediately
+// unless RevokeAfter is set, in which case it is left for Revoke or Run.
+func (r *KeyRotator) Rotate(ctx context.Context) (*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.revokeLocked(ctx); err != nil {
+		return nil, err
+	}
+
+	oldToken, err := r.creds.APIKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current API key: %w", err)
+	}
+	oldIdentifier, err := r.identifier(ctx, oldToken)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := r.client.CreateAPIKey(ctx, r.opts.Description, r.opts.AllowedIPs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+	if key.Meta.SecretToken == "" {
+		return nil, fmt.Errorf("created API key %s has no secret token", key.Identifier)
+	}
+
+	r.creds.Set(key.Meta.SecretToken)
+	if _, err := r.client.GetAccount(ctx); err != nil {
+		r.creds.Set(oldToken)
+		if delErr := r.client.DeleteAPIKey(ctx, key.Identifier); delErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to delete new API key: %w", delErr))
+		}
+		return nil, fmt.Errorf("failed to verify new API key: %w", err)
+	}
+
+	r.current = key.Identifier
+	r.previous = &previousKey{token: oldToken, identifier: oldIdentifier}
+	if r.opts.OnRotate != nil {
+		r.opts.OnRotate(key)
+	}
+	if r.opts.RevokeAfter <= 0 {
+		if err := r.revokeLocked(ctx); err != nil {
+			return key, err
+		}
+	}
+	return key, nil
+}
+
+// Rollback switches back to the key replaced by the last rotation and deletes
+// the key that rotation created. It returns ErrNoRollback if the previous key
+// was already revoked.
+func (r *KeyRotator) Rollback(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.previous == nil {
+		return ErrNoRollback
+	}
+	r.creds.Set(r.previous.token)
+	r.previous = nil
+	if err := r.client.DeleteAPIKey(ctx, r.current); err != nil {
+		return fmt.Errorf("failed to delete rolled back API key: %w", err)
+	}
+	r.current = ""
+	return nil
+}
+
+// Revoke deletes the key replaced by the last rotation, if it is still kept
+// for rollback.
+func (r *KeyRotator) Revoke(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.revokeLocked(ctx)
+}
+
+// Run rotates the key every interval until ctx is cancelled, revoking each
+// replaced key after RevokeAfter. Errors are passed to OnError and do not stop
+// the schedule. It returns the context error.
+func (r *KeyRotator) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("rotation interval must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var revoke <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := r.Rotate(ctx); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				r.report(err)
+				continue
+			}
+			if r.opts.RevokeAfter > 0 {
+				revoke = time.After(r.opts.RevokeAfter)
+			}
+		case <-revoke:
+			revoke = nil
+			if err := r.Revoke(ctx); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				r.report(err)
+			}
+		}
+	}
+}
+
+// revokeLocked deletes the previous key. r.mu must be held.
+func (r *KeyRotator) revokeLocked(ctx context.Context) error {
+	if r.previous == nil {
+		return nil
+	}
+	if r.previous.identifier != "" {
+		if err := r.client.DeleteAPIKey(ctx, r.previous.identifier); err != nil {
+			return fmt.Errorf("failed to revoke previous API key: %w", err)
+		}
+	}
+	r.previous = nil
+	return nil
+}
+
+// identifier returns the identifier of the account key with the given token,
+// or an empty string if it is not one of the account's keys. Tokens start with
+// their key's identifier.
+func (r *KeyRotator) identifier(ctx context.Context, token string) (string, error) {
+	keys, err := r.client.ListAPIKeys(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list API keys: %w", err)
+	}
+	for _, k := range keys {
+		if k.Identifier != "" && strings.HasPrefix(token, k.Identifier) {
+			return k.Identifier, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *KeyRotator) report(err error) {
+	if r.opts.OnError != nil {
+		r.opts.OnError(err)
+	}
+}
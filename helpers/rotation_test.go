@@ -0,0 +1,149 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl"
+	"github.com/idanyas/go-pterodactyl/client"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/ptest"
+	"github.com/idanyas/go-pterodactyl/transport"
+)
+
+// newRotationPanel returns a panel that only accepts registered keys and a
+// client using rotating credentials.
+func newRotationPanel(t *testing.T) (*ptest.Panel, *pterodactyl.Client, *transport.RotatingCredentials) {
+	t.Helper()
+	panel := ptest.NewPanel()
+	t.Cleanup(panel.Close)
+	user := panel.AddUser(models.User{Username: "owner", Email: "owner@example.com"})
+	panel.SetAccount(user.ID)
+	panel.RequireAPIKeys(ptest.DefaultAPIKey)
+
+	creds := transport.NewRotatingCredentials(ptest.DefaultAPIKey)
+	c, err := panel.Client(pterodactyl.WithClientCredentials(creds), pterodactyl.WithMaxRetries(1))
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+	return panel, c, creds
+}
+
+func keyIdentifiers(t *testing.T, c *pterodactyl.Client) []string {
+	t.Helper()
+	keys, err := c.Client().ListAPIKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ListAPIKeys() failed: %v", err)
+	}
+	ids := make([]string, len(keys))
+	for i, k := range keys {
+		ids[i] = k.Identifier
+	}
+	return ids
+}
+
+func TestKeyRotator_Rotate(t *testing.T) {
+	_, c, creds := newRotationPanel(t)
+	ctx := context.Background()
+
+	var rotated *models.APIKey
+	rotator := NewKeyRotator(c.Client(), creds, KeyRotatorOptions{
+		OnRotate: func(key *models.APIKey) { rotated = key },
+	})
+	key, err := rotator.Rotate(ctx)
+	if err != nil {
+		t.Fatalf("Rotate() failed: %v", err)
+	}
+	if rotated != key {
+		t.Errorf("OnRotate was not called with the new key")
+	}
+	if got, _ := creds.APIKey(ctx); got != key.Meta.SecretToken {
+		t.Errorf("credentials hold %q, want the new secret token", got)
+	}
+	if ids := keyIdentifiers(t, c); len(ids) != 1 || ids[0] != key.Identifier {
+		t.Errorf("account keys = %v, want only %s", ids, key.Identifier)
+	}
+	if err := rotator.Rollback(ctx); !errors.Is(err, ErrNoRollback) {
+		t.Errorf("Rollback() after revocation: expected ErrNoRollback, got %v", err)
+	}
+}
+
+func TestKeyRotator_Rollback(t *testing.T) {
+	_, c, creds := newRotationPanel(t)
+	ctx := context.Background()
+
+	rotator := NewKeyRotator(c.Client(), creds, KeyRotatorOptions{RevokeAfter: time.Hour})
+	if _, err := rotator.Rotate(ctx); err != nil {
+		t.Fatalf("Rotate() failed: %v", err)
+	}
+	if n := len(keyIdentifiers(t, c)); n != 2 {
+		t.Fatalf("expected the old key to be kept, got %d keys", n)
+	}
+
+	if err := rotator.Rollback(ctx); err != nil {
+		t.Fatalf("Rollback() failed: %v", err)
+	}
+	if got, _ := creds.APIKey(ctx); got != ptest.DefaultAPIKey {
+		t.Errorf("credentials hold %q after rollback, want the original key", got)
+	}
+	if n := len(keyIdentifiers(t, c)); n != 1 {
+		t.Errorf("expected the new key to be deleted, got %d keys", n)
+	}
+}
+
+// unverifiableClient fails the account lookup used to verify new keys.
+type unverifiableClient struct {
+	client.ClientClient
+}
+
+func (unverifiableClient) GetAccount(ctx context.Context) (*models.User, error) {
+	return nil, errors.New("unauthenticated")
+}
+
+func TestKeyRotator_RotateRollsBackUnusableKey(t *testing.T) {
+	_, c, creds := newRotationPanel(t)
+	ctx := context.Background()
+
+	rotator := NewKeyRotator(unverifiableClient{c.Client()}, creds, KeyRotatorOptions{})
+	if _, err := rotator.Rotate(ctx); err == nil {
+		t.Fatal("Rotate() succeeded although the new key could not be verified")
+	}
+
+	if got, _ := creds.APIKey(ctx); got != ptest.DefaultAPIKey {
+		t.Errorf("credentials hold %q, want the original key", got)
+	}
+	if n := len(keyIdentifiers(t, c)); n != 1 {
+		t.Errorf("expected the new key to be deleted, got %d keys", n)
+	}
+}
+
+func TestKeyRotator_Run(t *testing.T) {
+	_, c, creds := newRotationPanel(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rotations := make(chan *models.APIKey, 10)
+	rotator := NewKeyRotator(c.Client(), creds, KeyRotatorOptions{
+		OnRotate: func(key *models.APIKey) { rotations <- key },
+		OnError:  func(err error) { t.Errorf("rotation failed: %v", err) },
+	})
+	done := make(chan error, 1)
+	go func() { done <- rotator.Run(ctx, 10*time.Millisecond) }()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-rotations:
+		case <-time.After(time.Second):
+			t.Fatalf("rotation %d did not happen", i+1)
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+	if _, err := c.Client().GetAccount(context.Background()); err != nil {
+		t.Errorf("GetAccount() with the rotated key failed: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/websocket"
+)
+
+// Server power states reported by Wings.
+const (
+	StateOffline  = "offline"
+	StateStarting = "starting"
+	StateRunning  = "running"
+	StateStopping = "stopping"
+)
+
+const defaultPollInterval = 5 * time.Second
+
+// ErrServerCrashed is returned when a server goes offline after starting while
+// waiting for a state other than offline.
+var ErrServerCrashed = errors.New("server went offline after starting")
+
+// WaitOptions configures how a StateWaiter observes state changes.
+type WaitOptions struct {
+	// PollInterval is the interval between resource polls when the WebSocket
+	// is not available. Defaults to 5 seconds.
+	PollInterval time.Duration
+	// DisableWebSocket forces polling instead of listening for status events.
+	DisableWebSocket bool
+	// DisableCrashDetection disables the early failure on an offline state
+	// observed after a starting state.
+	DisableCrashDetection bool
+}
+
+func (o WaitOptions) pollInterval() time.Duration {
+	if o.PollInterval <= 0 {
+		return defaultPollInterval
+	}
+	return o.PollInterval
+}
+
+// WaitForStates waits until the server reaches any of the given states and returns
+// the state that was reached. Status events from the server's WebSocket are used
+// when available, falling back to polling GetServerResources otherwise.
+//
+// Unless crash detection is disabled, ErrServerCrashed is returned when the server
+// goes offline after starting and offline is not one of the acceptable states.
+func (w *StateWaiter) WaitForStates(ctx context.Context, serverID string, states []string, opts WaitOptions) (string, error) {
+	return w.wait(ctx, serverID, states, opts, nil)
+}
+
+// StartAndWait sends the start signal to a server and waits until it is running.
+func (w *StateWaiter) StartAndWait(ctx context.Context, serverID string, opts WaitOptions) error {
+	_, err := w.wait(ctx, serverID, []string{StateRunning}, opts, func(ctx context.Context) error {
+		return w.client.SendPowerAction(ctx, serverID, "start")
+	})
+	return err
+}
+
+// StopAndWait sends the stop signal to a server and waits until it is offline.
+func (w *StateWaiter) StopAndWait(ctx context.Context, serverID string, opts WaitOptions) error {
+	_, err := w.wait(ctx, serverID, []string{StateOffline}, opts, func(ctx context.Context) error {
+		return w.client.SendPowerAction(ctx, serverID, "stop")
+	})
+	return err
+}
+
+// wait subscribes to state changes, runs action (if any) and then blocks until
+// one of the acceptable states is observed. Subscribing before running the action
+// ensures short-lived transitions such as a crash during startup are not missed.
+func (w *StateWaiter) wait(ctx context.Context, serverID string, states []string, opts WaitOptions, action func(context.Context) error) (string, error) {
+	if len(states) == 0 {
+		return "", fmt.Errorf("at least one state is required")
+	}
+
+	tracker := newStateTracker(states, !opts.DisableCrashDetection)
+
+	var conn *websocket.Conn
+	if !opts.DisableWebSocket {
+		// A failed connection is not fatal; polling takes over instead.
+		if c, err := w.client.ConnectWebSocket(ctx, serverID); err == nil && c != nil {
+			conn = c
+			defer conn.Close()
+		}
+	}
+
+	if action != nil {
+		if err := action(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	// Check the current state right away instead of waiting for the first event or tick.
+	if state, done, err := w.poll(ctx, serverID, tracker); done || err != nil {
+		return state, err
+	}
+
+	if conn != nil {
+		if state, done, err := tracker.watch(ctx, conn.Events()); done || err != nil {
+			return state, err
+		}
+	}
+
+	ticker := time.NewTicker(opts.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+			if state, done, err := w.poll(ctx, serverID, tracker); done || err != nil {
+				return state, err
+			}
+		}
+	}
+}
+
+// poll fetches the current state of a server and feeds it to the tracker.
+func (w *StateWaiter) poll(ctx context.Context, serverID string, tracker *stateTracker) (string, bool, error) {
+	resources, err := w.client.GetServerResources(ctx, serverID)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get server resources: %w", err)
+	}
+	return tracker.observe(resources.CurrentState)
+}
+
+// stateTracker records observed states and decides when waiting is finished.
+type stateTracker struct {
+	accept      map[string]bool
+	detectCrash bool
+	sawStarting bool
+}
+
+func newStateTracker(states []string, detectCrash bool) *stateTracker {
+	accept := make(map[string]bool, len(states))
+	for _, s := range states {
+		accept[s] = true
+	}
+	return &stateTracker{accept: accept, detectCrash: detectCrash && !accept[StateOffline]}
+}
+
+// observe records a state and reports whether waiting is finished.
+func (t *stateTracker) observe(state string) (string, bool, error) {
+	if t.accept[state] {
+		return state, true, nil
+	}
+	switch state {
+	case StateStarting:
+		t.sawStarting = true
+	case StateOffline:
+		if t.detectCrash && t.sawStarting {
+			return state, true, ErrServerCrashed
+		}
+	}
+	return state, false, nil
+}
+
+// watch consumes status events until waiting is finished, the context is done or
+// the event channel is closed. A closed channel returns done=false so the caller
+// can fall back to polling.
+func (t *stateTracker) watch(ctx context.Context, events <-chan websocket.Event) (string, bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return "", true, ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return "", false, nil
+			}
+			switch e := event.(type) {
+			case *websocket.StatusEvent:
+				if state, done, err := t.observe(e.Status); done || err != nil {
+					return state, done, err
+				}
+			case *websocket.TokenExpiredEvent:
+				return "", false, nil
+			}
+		}
+	}
+}
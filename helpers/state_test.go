@@ -0,0 +1,198 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cws "github.com/coder/websocket"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/websocket"
+)
+
+// stateMock returns a scripted sequence of states from GetServerResources and
+// optionally serves status events over a test WebSocket.
+type stateMock struct {
+	mockClientForHelpers
+
+	mu       sync.Mutex
+	states   []string
+	polls    int
+	signals  []string
+	socket   string
+	onSignal func(signal string)
+	// signalErr is returned by SendPowerAction when set.
+	signalErr error
+}
+
+func (m *stateMock) GetServerResources(ctx context.Context, serverID string) (*models.Stats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state := m.states[len(m.states)-1]
+	if m.polls < len(m.states) {
+		state = m.states[m.polls]
+	}
+	m.polls++
+	return &models.Stats{CurrentState: state}, nil
+}
+
+func (m *stateMock) SendPowerAction(ctx context.Context, serverID, signal string) error {
+	m.mu.Lock()
+	m.signals = append(m.signals, signal)
+	onSignal, err := m.onSignal, m.signalErr
+	m.mu.Unlock()
+	if onSignal != nil {
+		onSignal(signal)
+	}
+	return err
+}
+
+func (m *stateMock) ConnectWebSocket(ctx context.Context, serverID string) (*websocket.Conn, error) {
+	if m.socket == "" {
+		return nil, errors.New("websocket unavailable")
+	}
+	return websocket.NewConn(ctx, m.socket, "test-token", nil)
+}
+
+// statusServer starts a WebSocket server that emits the statuses sent on the
+// returned channel.
+func statusServer(t *testing.T) (string, chan<- string) {
+	t.Helper()
+	statuses := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := cws.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close(cws.StatusNormalClosure, "")
+		if _, _, err := c.Read(r.Context()); err != nil {
+			return
+		}
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case status := <-statuses:
+				data, _ := json.Marshal(map[string]interface{}{"event": "status", "args": []string{status}})
+				if err := c.Write(r.Context(), cws.MessageText, data); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http"), statuses
+}
+
+func TestStateWaiter_WaitForState_ChecksImmediately(t *testing.T) {
+	mock := &stateMock{states: []string{StateRunning}}
+	waiter := NewStateWaiter(mock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if err := waiter.WaitForState(ctx, "abc", StateRunning, time.Hour); err != nil {
+		t.Fatalf("WaitForState() returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("WaitForState() took %v, expected an immediate check", elapsed)
+	}
+}
+
+func TestStateWaiter_WaitForStates_Polling(t *testing.T) {
+	mock := &stateMock{states: []string{StateOffline, StateStarting, StateStopping}}
+	waiter := NewStateWaiter(mock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	state, err := waiter.WaitForStates(ctx, "abc", []string{StateRunning, StateStopping}, WaitOptions{
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("WaitForStates() returned error: %v", err)
+	}
+	if state != StateStopping {
+		t.Errorf("state = %q, want %q", state, StateStopping)
+	}
+}
+
+func TestStateWaiter_WaitForStates_CrashDetection(t *testing.T) {
+	mock := &stateMock{states: []string{StateStarting, StateOffline, StateRunning}}
+	waiter := NewStateWaiter(mock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := waiter.WaitForStates(ctx, "abc", []string{StateRunning}, WaitOptions{
+		PollInterval: 10 * time.Millisecond,
+	})
+	if !errors.Is(err, ErrServerCrashed) {
+		t.Fatalf("WaitForStates() error = %v, want ErrServerCrashed", err)
+	}
+}
+
+func TestStateWaiter_WaitForStates_WebSocket(t *testing.T) {
+	socket, statuses := statusServer(t)
+	mock := &stateMock{states: []string{StateOffline}, socket: socket}
+	waiter := NewStateWaiter(mock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	statuses <- StateStarting
+	statuses <- StateRunning
+
+	state, err := waiter.WaitForStates(ctx, "abc", []string{StateRunning}, WaitOptions{
+		PollInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("WaitForStates() returned error: %v", err)
+	}
+	if state != StateRunning {
+		t.Errorf("state = %q, want %q", state, StateRunning)
+	}
+}
+
+func TestStateWaiter_StartAndWait_Crash(t *testing.T) {
+	socket, statuses := statusServer(t)
+	mock := &stateMock{states: []string{StateOffline}, socket: socket}
+	mock.onSignal = func(signal string) {
+		statuses <- StateStarting
+		statuses <- StateOffline
+	}
+	waiter := NewStateWaiter(mock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err := waiter.StartAndWait(ctx, "abc", WaitOptions{PollInterval: time.Hour})
+	if !errors.Is(err, ErrServerCrashed) {
+		t.Fatalf("StartAndWait() error = %v, want ErrServerCrashed", err)
+	}
+	if len(mock.signals) != 1 || mock.signals[0] != "start" {
+		t.Errorf("signals = %v, want [start]", mock.signals)
+	}
+}
+
+func TestStateWaiter_StopAndWait(t *testing.T) {
+	mock := &stateMock{states: []string{StateRunning, StateStopping, StateOffline}}
+	waiter := NewStateWaiter(mock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := waiter.StopAndWait(ctx, "abc", WaitOptions{PollInterval: 10 * time.Millisecond}); err != nil {
+		t.Fatalf("StopAndWait() returned error: %v", err)
+	}
+	if len(mock.signals) != 1 || mock.signals[0] != "stop" {
+		t.Errorf("signals = %v, want [stop]", mock.signals)
+	}
+}
@@ -0,0 +1,166 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/websocket"
+)
+
+const defaultGracePeriod = 30 * time.Second
+
+// StopOutcome describes how a server was brought offline by GracefulStop.
+type StopOutcome string
+
+const (
+	// StopAlreadyOffline means the server was offline before anything was sent.
+	StopAlreadyOffline StopOutcome = "already_offline"
+	// StopGraceful means the server went offline within the grace period.
+	StopGraceful StopOutcome = "graceful"
+	// StopKilled means the server did not stop in time and was killed.
+	StopKilled StopOutcome = "killed"
+)
+
+// GracefulStopOptions configures GracefulStop.
+type GracefulStopOptions struct {
+	// GracePeriod is how long to wait for the server to go offline after the
+	// stop was sent before sending kill. Defaults to 30 seconds.
+	GracePeriod time.Duration
+	// KillTimeout bounds the wait for the offline state after kill was sent.
+	// Zero waits until the context is done.
+	KillTimeout time.Duration
+	// Conn, when set, is used to send power and console commands instead of the
+	// REST API. Its status events are still observed through a separate waiter.
+	Conn *websocket.Conn
+	// Egg, when set, causes the egg's configured stop command (EggConfig.Stop)
+	// to be sent to the console instead of the stop signal. Signal-style stop
+	// values such as "^C" fall back to the stop signal.
+	Egg *models.Egg
+	// Supervisor, when set, is told to expect the server going offline, so
+	// neither a console stop command nor kill is reported as a crash. The
+	// expectation is withdrawn if GracefulStop fails.
+	Supervisor *Supervisor
+	// Wait configures how state changes are observed.
+	Wait WaitOptions
+}
+
+// EggStopCommand returns the console command configured to stop servers of an egg.
+// An empty string is returned when the egg stops through a signal (e.g. "^C") or
+// has no stop configuration.
+func EggStopCommand(egg *models.Egg) string {
+	if egg == nil {
+		return ""
+	}
+	stop := strings.TrimSpace(egg.Config.Stop)
+	if stop == "" || strings.HasPrefix(stop, "^") {
+		return ""
+	}
+	return stop
+}
+
+// GracefulStop asks a server to stop and waits up to the grace period for it to go
+// offline. If the server is still running after the grace period, kill is sent.
+// The returned StopOutcome reports which path was taken.
+func (w *StateWaiter) GracefulStop(ctx context.Context, serverID string, opts GracefulStopOptions) (StopOutcome, error) {
+	grace := opts.GracePeriod
+	if grace <= 0 {
+		grace = defaultGracePeriod
+	}
+
+	// Crash detection is meaningless when the target state is offline.
+	waitOpts := opts.Wait
+	waitOpts.DisableCrashDetection = true
+
+	// Check the current state first so an offline server is left untouched.
+	tracker := newStateTracker([]string{StateOffline}, false)
+	if _, done, err := w.poll(ctx, serverID, tracker); err != nil {
+		return "", err
+	} else if done {
+		return StopAlreadyOffline, nil
+	}
+	unexpect := func() {}
+	if opts.Supervisor != nil {
+		unexpect = opts.Supervisor.ExpectStop(serverID)
+	}
+
+	err := w.waitAfter(ctx, serverID, waitOpts, grace, func(ctx context.Context) error {
+		return w.sendStop(ctx, serverID, opts)
+	})
+	if err == nil {
+		return StopGraceful, nil
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+		unexpect()
+		return "", err
+	}
+
+	err = w.waitAfter(ctx, serverID, waitOpts, opts.KillTimeout, func(ctx context.Context) error {
+		return w.sendSignal(ctx, serverID, "kill", opts.Conn)
+	})
+	if err != nil {
+		unexpect()
+		return StopKilled, fmt.Errorf("failed to wait for server %s after kill: %w", serverID, err)
+	}
+	return StopKilled, nil
+}
+
+// waitAfter sends action and waits for the server to go offline. The timeout,
+// if positive, starts once action has been sent, so connecting and sending do
+// not count against it; when it elapses, context.DeadlineExceeded is returned.
+func (w *StateWaiter) waitAfter(ctx context.Context, serverID string, opts WaitOptions, timeout time.Duration, action func(context.Context) error) error {
+	if timeout <= 0 {
+		_, err := w.wait(ctx, serverID, []string{StateOffline}, opts, action)
+		return err
+	}
+
+	waitCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	var timer *time.Timer
+	_, err := w.wait(waitCtx, serverID, []string{StateOffline}, opts, func(context.Context) error {
+		if err := action(ctx); err != nil {
+			return err
+		}
+		timer = time.AfterFunc(timeout, func() { cancel(context.DeadlineExceeded) })
+		return nil
+	})
+	if timer != nil {
+		timer.Stop()
+	}
+	if err != nil && ctx.Err() == nil && errors.Is(context.Cause(waitCtx), context.DeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// sendStop sends the egg stop command when requested, or the stop signal otherwise.
+func (w *StateWaiter) sendStop(ctx context.Context, serverID string, opts GracefulStopOptions) error {
+	command := EggStopCommand(opts.Egg)
+	if command == "" {
+		return w.sendSignal(ctx, serverID, "stop", opts.Conn)
+	}
+	if opts.Conn != nil {
+		if err := opts.Conn.SendCommand(command); err != nil {
+			return fmt.Errorf("failed to send stop command: %w", err)
+		}
+		return nil
+	}
+	if err := w.client.SendCommand(ctx, serverID, command); err != nil {
+		return fmt.Errorf("failed to send stop command: %w", err)
+	}
+	return nil
+}
+
+// sendSignal sends a power signal over conn when provided, or the REST API otherwise.
+func (w *StateWaiter) sendSignal(ctx context.Context, serverID, signal string, conn *websocket.Conn) error {
+	if conn != nil {
+		if err := conn.SetState(signal); err != nil {
+			return fmt.Errorf("failed to send %s signal: %w", signal, err)
+		}
+		return nil
+	}
+	return w.client.SendPowerAction(ctx, serverID, signal)
+}
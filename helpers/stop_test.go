@@ -0,0 +1,131 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+// setStates replaces the scripted states and restarts the sequence.
+func (m *stateMock) setStates(states ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states = states
+	m.polls = 0
+}
+
+func TestEggStopCommand(t *testing.T) {
+	tests := []struct {
+		stop string
+		want string
+	}{
+		{stop: "stop", want: "stop"},
+		{stop: " end ", want: "end"},
+		{stop: "^C", want: ""},
+		{stop: "^^C", want: ""},
+		{stop: "", want: ""},
+	}
+	for _, tt := range tests {
+		egg := &models.Egg{Config: models.EggConfig{Stop: tt.stop}}
+		if got := EggStopCommand(egg); got != tt.want {
+			t.Errorf("EggStopCommand(%q) = %q, want %q", tt.stop, got, tt.want)
+		}
+	}
+	if got := EggStopCommand(nil); got != "" {
+		t.Errorf("EggStopCommand(nil) = %q, want empty", got)
+	}
+}
+
+func TestStateWaiter_GracefulStop_AlreadyOffline(t *testing.T) {
+	mock := &stateMock{states: []string{StateOffline}}
+	waiter := NewStateWaiter(mock)
+
+	outcome, err := waiter.GracefulStop(context.Background(), "abc", GracefulStopOptions{})
+	if err != nil {
+		t.Fatalf("GracefulStop() returned error: %v", err)
+	}
+	if outcome != StopAlreadyOffline {
+		t.Errorf("outcome = %q, want %q", outcome, StopAlreadyOffline)
+	}
+	if len(mock.signals) != 0 {
+		t.Errorf("signals = %v, want none", mock.signals)
+	}
+}
+
+func TestStateWaiter_GracefulStop_Graceful(t *testing.T) {
+	mock := &stateMock{states: []string{StateRunning}}
+	mock.onSignal = func(signal string) {
+		mock.setStates(StateStopping, StateOffline)
+	}
+	waiter := NewStateWaiter(mock)
+
+	outcome, err := waiter.GracefulStop(context.Background(), "abc", GracefulStopOptions{
+		GracePeriod: time.Second,
+		Wait:        WaitOptions{PollInterval: 10 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("GracefulStop() returned error: %v", err)
+	}
+	if outcome != StopGraceful {
+		t.Errorf("outcome = %q, want %q", outcome, StopGraceful)
+	}
+	if len(mock.signals) != 1 || mock.signals[0] != "stop" {
+		t.Errorf("signals = %v, want [stop]", mock.signals)
+	}
+}
+
+func TestStateWaiter_GracefulStop_SlowStop(t *testing.T) {
+	mock := &stateMock{states: []string{StateRunning}}
+	mock.onSignal = func(signal string) {
+		// Sending the stop takes longer than the grace period, which only
+		// starts once it was sent.
+		time.Sleep(100 * time.Millisecond)
+		mock.setStates(StateStopping, StateStopping, StateOffline)
+	}
+	waiter := NewStateWaiter(mock)
+
+	outcome, err := waiter.GracefulStop(context.Background(), "abc", GracefulStopOptions{
+		GracePeriod: 60 * time.Millisecond,
+		Wait:        WaitOptions{PollInterval: 10 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("GracefulStop() returned error: %v", err)
+	}
+	if outcome != StopGraceful {
+		t.Errorf("outcome = %q, want %q", outcome, StopGraceful)
+	}
+}
+
+func TestStateWaiter_GracefulStop_Killed(t *testing.T) {
+	mock := &stateMock{states: []string{StateRunning}}
+	mock.onSignal = func(signal string) {
+		if signal == "kill" {
+			mock.setStates(StateOffline)
+		} else {
+			mock.setStates(StateStopping)
+		}
+	}
+	waiter := NewStateWaiter(mock)
+	supervisor := NewSupervisor(mock, SupervisorOptions{})
+
+	outcome, err := waiter.GracefulStop(context.Background(), "abc", GracefulStopOptions{
+		GracePeriod: 50 * time.Millisecond,
+		KillTimeout: time.Second,
+		Supervisor:  supervisor,
+		Wait:        WaitOptions{PollInterval: 10 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("GracefulStop() returned error: %v", err)
+	}
+	if outcome != StopKilled {
+		t.Errorf("outcome = %q, want %q", outcome, StopKilled)
+	}
+	if len(mock.signals) != 2 || mock.signals[0] != "stop" || mock.signals[1] != "kill" {
+		t.Errorf("signals = %v, want [stop kill]", mock.signals)
+	}
+	if !supervisor.stopExpected("abc") {
+		t.Error("the supervisor was not told to expect the stop")
+	}
+}
@@ -0,0 +1,409 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/client"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/websocket"
+)
+
+const (
+	defaultRestartBackoff    = 5 * time.Second
+	defaultRestartMaxBackoff = 5 * time.Minute
+	defaultReconnectDelay    = 5 * time.Second
+	defaultMemorySamples     = 3
+)
+
+// ErrRestartLimitExceeded is returned by Supervise when a server needed more
+// restarts than the restart policy allows.
+var ErrRestartLimitExceeded = errors.New("restart limit exceeded")
+
+// IncidentReason identifies why the supervisor decided a server needs a restart.
+type IncidentReason string
+
+const (
+	// IncidentCrash is an unexpected transition to offline, i.e. one that was
+	// not preceded by the stopping state or announced with ExpectStop.
+	IncidentCrash IncidentReason = "crash"
+	// IncidentMemory is sustained memory usage above the configured threshold.
+	IncidentMemory IncidentReason = "memory"
+	// IncidentConsole is a console line matching one of the configured patterns.
+	IncidentConsole IncidentReason = "console"
+)
+
+// Incident describes a problem detected by the supervisor.
+type Incident struct {
+	ServerID string
+	Reason   IncidentReason
+	// State is the last status reported by the server.
+	State string
+	// Line is the matching console line for IncidentConsole.
+	Line string
+	// Resources is the last stats sample received from the server.
+	Resources models.Resources
+	Time      time.Time
+}
+
+// RestartPolicy controls how the supervisor restarts servers.
+type RestartPolicy struct {
+	// MaxRestarts is the maximum number of restarts within Window (0 = unlimited).
+	MaxRestarts int
+	// Window is the period over which restarts are counted. Zero counts restarts
+	// for the lifetime of the supervisor.
+	Window time.Duration
+	// InitialBackoff is the delay before the first restart. Defaults to 5 seconds.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay before a restart. Defaults to 5 minutes.
+	MaxBackoff time.Duration
+	// Multiplier is the exponential backoff multiplier. Defaults to 2.
+	Multiplier float64
+}
+
+// backoff returns the delay before the n-th restart (starting at 0).
+func (p RestartPolicy) backoff(n int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = defaultRestartBackoff
+	}
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = defaultRestartMaxBackoff
+	}
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	d := float64(initial) * math.Pow(multiplier, float64(n))
+	if d > float64(max) {
+		return max
+	}
+	return time.Duration(d)
+}
+
+// SupervisorHooks receive notifications about supervisor actions.
+// All hooks are optional and may be called concurrently for different servers.
+type SupervisorHooks struct {
+	// OnIncident is called when a problem is detected.
+	OnIncident func(incident Incident)
+	// OnRestart is called after a restart signal was sent successfully.
+	// attempt counts restarts within the policy window, starting at 1.
+	OnRestart func(incident Incident, signal string, attempt int)
+	// OnRestartError is called when sending a restart signal fails.
+	OnRestartError func(incident Incident, err error)
+	// OnGiveUp is called when the restart policy is exhausted and the server is
+	// no longer supervised.
+	OnGiveUp func(incident Incident)
+	// OnDisconnect is called when the WebSocket could not be established or was lost.
+	OnDisconnect func(serverID string, err error)
+}
+
+// SupervisorOptions configures a Supervisor.
+type SupervisorOptions struct {
+	// Policy controls restart backoff and limits.
+	Policy RestartPolicy
+	// Hooks receive notifications about supervisor actions.
+	Hooks SupervisorHooks
+	// MemoryThreshold is the fraction of Resources.MemoryLimitBytes at which a
+	// stats sample counts as memory pressure (e.g. 0.95). Zero disables memory checks.
+	MemoryThreshold float64
+	// MemorySamples is the number of consecutive samples above MemoryThreshold
+	// required to report an incident. Defaults to 3.
+	MemorySamples int
+	// ConsolePatterns are matched against each console line.
+	ConsolePatterns []*regexp.Regexp
+	// ReconnectDelay is the delay before reconnecting a lost WebSocket. Defaults to 5 seconds.
+	ReconnectDelay time.Duration
+}
+
+// Supervisor watches servers through their WebSocket and restarts them when
+// they crash, run out of memory or print matching console lines.
+type Supervisor struct {
+	client client.ClientClient
+	opts   SupervisorOptions
+
+	mu       sync.Mutex
+	expected map[string]bool
+}
+
+// NewSupervisor creates a new Supervisor.
+func NewSupervisor(c client.ClientClient, opts SupervisorOptions) *Supervisor {
+	if opts.MemorySamples <= 0 {
+		opts.MemorySamples = defaultMemorySamples
+	}
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = defaultReconnectDelay
+	}
+	return &Supervisor{client: c, opts: opts, expected: make(map[string]bool)}
+}
+
+// ExpectStop tells the supervisor that the server is about to be stopped on
+// purpose, so its next transition to offline is not reported as a crash even
+// if it skips the stopping state, as it does when killed. Call the returned
+// function if the stop fails, so that a later crash is detected again.
+func (s *Supervisor) ExpectStop(serverID string) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expected[serverID] = true
+	return func() { s.stopExpected(serverID) }
+}
+
+// stopExpected reports whether a stop of the server was announced with
+// ExpectStop and clears the announcement.
+func (s *Supervisor) stopExpected(serverID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expected := s.expected[serverID]
+	delete(s.expected, serverID)
+	return expected
+}
+
+// Run supervises the given servers until the context is done or every server
+// has exhausted its restart policy. It returns the joined give-up errors, or nil
+// when stopped through the context.
+func (s *Supervisor) Run(ctx context.Context, serverIDs ...string) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, id := range serverIDs {
+		wg.Add(1)
+		go func(serverID string) {
+			defer wg.Done()
+			if err := s.Supervise(ctx, serverID); err != nil && ctx.Err() == nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(id)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Supervise watches a single server until the context is done or the restart
+// policy is exhausted, in which case an error wrapping ErrRestartLimitExceeded
+// is returned.
+func (s *Supervisor) Supervise(ctx context.Context, serverID string) error {
+	w := &serverWatch{serverID: serverID}
+
+	for {
+		conn, err := s.client.ConnectWebSocket(ctx, serverID)
+		if err == nil && conn == nil {
+			err = fmt.Errorf("no websocket connection returned")
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.disconnected(serverID, err)
+		} else {
+			err = s.watch(ctx, conn, w)
+			conn.Close()
+			if err != nil {
+				return err
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.disconnected(serverID, fmt.Errorf("websocket connection lost"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.opts.ReconnectDelay):
+		}
+	}
+}
+
+func (s *Supervisor) disconnected(serverID string, err error) {
+	if s.opts.Hooks.OnDisconnect != nil {
+		s.opts.Hooks.OnDisconnect(serverID, err)
+	}
+}
+
+// serverWatch is the per-server supervision state. It survives reconnects.
+type serverWatch struct {
+	serverID   string
+	state      string
+	resources  models.Resources
+	overMemory int
+	// restarting suppresses memory and console incidents between a restart
+	// signal and the server reporting running again.
+	restarting bool
+	restarts   []time.Time
+	// pending is the incident of a scheduled restart, due at restartAt.
+	pending   *Incident
+	restartAt time.Time
+}
+
+// watch consumes events from conn until it is closed, the context is done, or
+// the restart policy is exhausted. Restarts are scheduled on a timer, so events
+// keep being read during the backoff; a restart still pending when the
+// connection is lost is carried over to the next one.
+func (s *Supervisor) watch(ctx context.Context, conn *websocket.Conn, w *serverWatch) error {
+	timer := time.NewTimer(0)
+	timer.Stop()
+	defer timer.Stop()
+	var due <-chan time.Time
+	if w.pending != nil {
+		timer.Reset(time.Until(w.restartAt))
+		due = timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-due:
+			due = nil
+			incident := *w.pending
+			w.pending = nil
+			s.restart(ctx, w, incident)
+		case event, ok := <-conn.Events():
+			if !ok {
+				return nil
+			}
+			incident := s.inspect(w, event)
+			// A crashed server that was started by someone else meanwhile
+			// needs no restart.
+			if w.pending != nil && w.pending.Reason == IncidentCrash && w.state == StateRunning {
+				w.pending, due = nil, nil
+			}
+			// Problems noticed while a restart is pending are part of the
+			// same incident.
+			if incident == nil || w.pending != nil {
+				continue
+			}
+			delay, err := s.handle(w, *incident)
+			if err != nil {
+				return err
+			}
+			w.pending, w.restartAt = incident, time.Now().Add(delay)
+			timer.Reset(delay)
+			due = timer.C
+		}
+	}
+}
+
+// inspect updates the watch state with an event and returns an incident if one was detected.
+func (s *Supervisor) inspect(w *serverWatch, event websocket.Event) *Incident {
+	switch e := event.(type) {
+	case *websocket.StatusEvent:
+		previous := w.state
+		w.state = e.Status
+		switch e.Status {
+		case StateRunning:
+			w.restarting = false
+		case StateOffline:
+			w.overMemory = 0
+			if s.stopExpected(w.serverID) {
+				return nil
+			}
+			if previous == StateRunning || previous == StateStarting {
+				return w.incident(IncidentCrash, "")
+			}
+		}
+
+	case *websocket.StatsEvent:
+		w.resources = e.Stats
+		if s.opts.MemoryThreshold <= 0 || e.Stats.MemoryLimitBytes <= 0 || w.restarting {
+			return nil
+		}
+		if float64(e.Stats.MemoryBytes) >= s.opts.MemoryThreshold*float64(e.Stats.MemoryLimitBytes) {
+			w.overMemory++
+		} else {
+			w.overMemory = 0
+		}
+		if w.overMemory >= s.opts.MemorySamples {
+			w.overMemory = 0
+			return w.incident(IncidentMemory, "")
+		}
+
+	case *websocket.ConsoleOutputEvent:
+		if w.restarting {
+			return nil
+		}
+		for _, pattern := range s.opts.ConsolePatterns {
+			if pattern.MatchString(e.Line) {
+				return w.incident(IncidentConsole, e.Line)
+			}
+		}
+	}
+	return nil
+}
+
+func (w *serverWatch) incident(reason IncidentReason, line string) *Incident {
+	return &Incident{
+		ServerID:  w.serverID,
+		Reason:    reason,
+		State:     w.state,
+		Line:      line,
+		Resources: w.resources,
+		Time:      time.Now(),
+	}
+}
+
+// handle applies the restart policy to an incident and returns the delay before
+// the server should be restarted.
+func (s *Supervisor) handle(w *serverWatch, incident Incident) (time.Duration, error) {
+	hooks := s.opts.Hooks
+	if hooks.OnIncident != nil {
+		hooks.OnIncident(incident)
+	}
+
+	policy := s.opts.Policy
+	if policy.Window > 0 {
+		cutoff := incident.Time.Add(-policy.Window)
+		kept := w.restarts[:0]
+		for _, t := range w.restarts {
+			if t.After(cutoff) {
+				kept = append(kept, t)
+			}
+		}
+		w.restarts = kept
+	}
+	if policy.MaxRestarts > 0 && len(w.restarts) >= policy.MaxRestarts {
+		if hooks.OnGiveUp != nil {
+			hooks.OnGiveUp(incident)
+		}
+		return 0, fmt.Errorf("server %s: %w after %d restarts", w.serverID, ErrRestartLimitExceeded, len(w.restarts))
+	}
+	return policy.backoff(len(w.restarts)), nil
+}
+
+// restart sends the restart signal for an incident once its backoff has passed.
+func (s *Supervisor) restart(ctx context.Context, w *serverWatch, incident Incident) {
+	hooks := s.opts.Hooks
+
+	// A crashed server only needs to be started; otherwise restart it.
+	signal := "restart"
+	if incident.Reason == IncidentCrash {
+		signal = "start"
+		// Skip the restart if the server was started by someone else meanwhile.
+		if stats, err := s.client.GetServerResources(ctx, w.serverID); err == nil && stats.CurrentState != StateOffline {
+			return
+		}
+	}
+
+	w.restarts = append(w.restarts, time.Now())
+	if err := s.client.SendPowerAction(ctx, w.serverID, signal); err != nil {
+		if hooks.OnRestartError != nil {
+			hooks.OnRestartError(incident, err)
+		}
+		return
+	}
+	w.restarting = true
+	if hooks.OnRestart != nil {
+		hooks.OnRestart(incident, signal, len(w.restarts))
+	}
+}
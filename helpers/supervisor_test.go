@@ -0,0 +1,287 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cws "github.com/coder/websocket"
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+// eventServer starts a WebSocket server that emits the given events to every
+// connection and then either keeps the connection open or closes it.
+func eventServer(t *testing.T, keepOpen bool, events ...[2]string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := cws.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close(cws.StatusNormalClosure, "")
+		if _, _, err := c.Read(r.Context()); err != nil {
+			return
+		}
+		for _, e := range events {
+			data, _ := json.Marshal(map[string]interface{}{"event": e[0], "args": []string{e[1]}})
+			if err := c.Write(r.Context(), cws.MessageText, data); err != nil {
+				return
+			}
+		}
+		if keepOpen {
+			<-r.Context().Done()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func statsJSON(memory, limit int64) string {
+	data, _ := json.Marshal(models.Resources{MemoryBytes: memory, MemoryLimitBytes: limit})
+	return string(data)
+}
+
+func TestRestartPolicy_Backoff(t *testing.T) {
+	p := RestartPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.backoff(i); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestSupervisor_RestartsAfterCrash(t *testing.T) {
+	socket := eventServer(t, true,
+		[2]string{"status", StateRunning},
+		[2]string{"status", StateOffline},
+	)
+	mock := &stateMock{states: []string{StateOffline}, socket: socket}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	restarted := make(chan string, 1)
+	s := NewSupervisor(mock, SupervisorOptions{
+		Policy: RestartPolicy{InitialBackoff: time.Millisecond},
+		Hooks: SupervisorHooks{
+			OnRestart: func(incident Incident, signal string, attempt int) {
+				if incident.Reason != IncidentCrash {
+					t.Errorf("reason = %q, want %q", incident.Reason, IncidentCrash)
+				}
+				restarted <- signal
+			},
+		},
+	})
+	go s.Supervise(ctx, "abc")
+
+	select {
+	case signal := <-restarted:
+		if signal != "start" {
+			t.Errorf("signal = %q, want start", signal)
+		}
+	case <-ctx.Done():
+		t.Fatal("supervisor did not restart the server")
+	}
+}
+
+func TestSupervisor_IgnoresRequestedStop(t *testing.T) {
+	socket := eventServer(t, true,
+		[2]string{"status", StateRunning},
+		[2]string{"status", StateStopping},
+		[2]string{"status", StateOffline},
+	)
+	mock := &stateMock{states: []string{StateOffline}, socket: socket}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var incidents int
+	s := NewSupervisor(mock, SupervisorOptions{
+		Policy: RestartPolicy{InitialBackoff: time.Millisecond},
+		Hooks:  SupervisorHooks{OnIncident: func(Incident) { incidents++ }},
+	})
+	if err := s.Supervise(ctx, "abc"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Supervise() error = %v, want context.DeadlineExceeded", err)
+	}
+	if incidents != 0 || len(mock.signals) != 0 {
+		t.Errorf("incidents = %d, signals = %v, want none", incidents, mock.signals)
+	}
+}
+
+func TestSupervisor_IgnoresExpectedStop(t *testing.T) {
+	socket := eventServer(t, true,
+		[2]string{"status", StateRunning},
+		[2]string{"status", StateOffline},
+	)
+	mock := &stateMock{states: []string{StateOffline}, socket: socket}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var incidents int
+	s := NewSupervisor(mock, SupervisorOptions{
+		Policy: RestartPolicy{InitialBackoff: time.Millisecond},
+		Hooks:  SupervisorHooks{OnIncident: func(Incident) { incidents++ }},
+	})
+	s.ExpectStop("abc")
+	if err := s.Supervise(ctx, "abc"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Supervise() error = %v, want context.DeadlineExceeded", err)
+	}
+	if incidents != 0 || len(mock.signals) != 0 {
+		t.Errorf("incidents = %d, signals = %v, want none", incidents, mock.signals)
+	}
+}
+
+func TestSupervisor_RestartsAfterFailedStop(t *testing.T) {
+	socket := eventServer(t, true,
+		[2]string{"status", StateRunning},
+		[2]string{"status", StateOffline},
+	)
+	mock := &stateMock{states: []string{StateRunning}, socket: socket, signalErr: errors.New("panel unavailable")}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	restarted := make(chan string, 1)
+	s := NewSupervisor(mock, SupervisorOptions{
+		Policy: RestartPolicy{InitialBackoff: time.Millisecond},
+		Hooks: SupervisorHooks{
+			OnRestart: func(incident Incident, signal string, attempt int) { restarted <- signal },
+		},
+	})
+
+	_, err := NewStateWaiter(mock).GracefulStop(ctx, "abc", GracefulStopOptions{
+		Supervisor: s,
+		Wait:       WaitOptions{DisableWebSocket: true},
+	})
+	if err == nil {
+		t.Fatal("GracefulStop() succeeded, want the stop to fail")
+	}
+
+	// The failed stop must not hide the next crash.
+	mock.mu.Lock()
+	mock.states, mock.polls, mock.signalErr = []string{StateOffline}, 0, nil
+	mock.mu.Unlock()
+	go s.Supervise(ctx, "abc")
+
+	select {
+	case signal := <-restarted:
+		if signal != "start" {
+			t.Errorf("signal = %q, want start", signal)
+		}
+	case <-ctx.Done():
+		t.Fatal("supervisor did not restart the server after a failed stop")
+	}
+}
+
+func TestSupervisor_ReadsEventsDuringBackoff(t *testing.T) {
+	socket := eventServer(t, true,
+		[2]string{"status", StateRunning},
+		[2]string{"status", StateOffline},
+		[2]string{"status", StateStarting},
+		[2]string{"status", StateRunning},
+	)
+	mock := &stateMock{states: []string{StateOffline}, socket: socket}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	var incidents int
+	s := NewSupervisor(mock, SupervisorOptions{
+		Policy: RestartPolicy{InitialBackoff: 100 * time.Millisecond},
+		Hooks:  SupervisorHooks{OnIncident: func(Incident) { incidents++ }},
+	})
+	if err := s.Supervise(ctx, "abc"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Supervise() error = %v, want context.DeadlineExceeded", err)
+	}
+	// The server came back during the backoff, so the restart is dropped.
+	if incidents != 1 || len(mock.signals) != 0 {
+		t.Errorf("incidents = %d, signals = %v, want 1 incident and no signals", incidents, mock.signals)
+	}
+}
+
+func TestSupervisor_MemoryAndConsole(t *testing.T) {
+	socket := eventServer(t, true,
+		[2]string{"status", StateRunning},
+		[2]string{"stats", statsJSON(990, 1000)},
+		[2]string{"stats", statsJSON(995, 1000)},
+		[2]string{"console output", "java.lang.OutOfMemoryError: Java heap space"},
+	)
+	mock := &stateMock{states: []string{StateRunning}, socket: socket}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var reasons []IncidentReason
+	done := make(chan struct{})
+	s := NewSupervisor(mock, SupervisorOptions{
+		Policy:          RestartPolicy{InitialBackoff: time.Millisecond},
+		MemoryThreshold: 0.95,
+		MemorySamples:   2,
+		ConsolePatterns: []*regexp.Regexp{regexp.MustCompile(`OutOfMemoryError`)},
+		Hooks: SupervisorHooks{
+			OnIncident: func(incident Incident) {
+				mu.Lock()
+				reasons = append(reasons, incident.Reason)
+				mu.Unlock()
+			},
+			OnRestart: func(incident Incident, signal string, attempt int) {
+				if signal != "restart" {
+					t.Errorf("signal = %q, want restart", signal)
+				}
+				close(done)
+			},
+		},
+	})
+	go s.Supervise(ctx, "abc")
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("supervisor did not restart the server")
+	}
+
+	// The console incident is suppressed while the restart is pending.
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reasons) != 1 || reasons[0] != IncidentMemory {
+		t.Errorf("reasons = %v, want [%s]", reasons, IncidentMemory)
+	}
+}
+
+func TestSupervisor_GivesUp(t *testing.T) {
+	socket := eventServer(t, false,
+		[2]string{"status", StateStarting},
+		[2]string{"status", StateOffline},
+	)
+	mock := &stateMock{states: []string{StateOffline}, socket: socket}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var gaveUp bool
+	s := NewSupervisor(mock, SupervisorOptions{
+		Policy:         RestartPolicy{MaxRestarts: 1, InitialBackoff: time.Millisecond},
+		ReconnectDelay: time.Millisecond,
+		Hooks:          SupervisorHooks{OnGiveUp: func(Incident) { gaveUp = true }},
+	})
+
+	// Every reconnect replays a crash, so the second one exhausts the policy.
+	err := s.Run(ctx, "abc")
+	if !errors.Is(err, ErrRestartLimitExceeded) {
+		t.Fatalf("Run() error = %v, want ErrRestartLimitExceeded", err)
+	}
+	if !gaveUp {
+		t.Error("OnGiveUp was not called")
+	}
+}
@@ -0,0 +1,96 @@
+// Package redact removes credentials from values before they are shown in
+// errors, logs or recordings.
+package redact
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// Placeholder replaces removed credentials.
+const Placeholder = "REDACTED"
+
+// sensitiveQuery lists query parameters that carry credentials, such as the
+// token of signed download and upload URLs and the secret of 2FA otpauth URLs.
+var sensitiveQuery = []string{"token", "secret"}
+
+// sensitiveHeaders lists headers that carry credentials.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+var (
+	// urlToken matches credential parameters of URLs embedded in text. JSON
+	// may escape the separator.
+	urlToken = regexp.MustCompile(`((?:\?|&|\\u0026)(?:token|secret)=)[^&"\s\\]+`)
+	// jsonSecret matches JSON fields holding secrets, such as WebSocket and
+	// node tokens, new API key secrets, and database and account passwords.
+	jsonSecret = regexp.MustCompile(`("(?:token|secret_token|secret|password|current_password|password_confirmation)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// bearer matches bearer credentials.
+	bearer = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+	// apiKey matches full Pterodactyl API keys. Key identifiers, which are
+	// shorter, are left alone.
+	apiKey = regexp.MustCompile(`ptl[ac]_[A-Za-z0-9]{32,}`)
+)
+
+// Secret returns Placeholder for a non-empty secret, and an empty string
+// otherwise, so logs still show whether a secret was set.
+func Secret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return Placeholder
+}
+
+// URL returns rawURL with credentials in its query and user info replaced.
+// Text that does not parse as a URL is redacted like String.
+func URL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return String(rawURL)
+	}
+	changed := false
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), Placeholder)
+		changed = true
+	}
+	q := u.Query()
+	for _, name := range sensitiveQuery {
+		if q.Has(name) {
+			q.Set(name, Placeholder)
+			changed = true
+		}
+	}
+	if !changed {
+		return rawURL
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Header returns a copy of h with the values of credential headers replaced.
+func Header(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := out[name]; ok {
+			out.Set(name, Placeholder)
+		}
+	}
+	return out
+}
+
+// String replaces credentials found in free text or JSON: credential
+// parameters of URLs, secret JSON fields, bearer tokens and API keys.
+func String(s string) string {
+	s = urlToken.ReplaceAllString(s, "${1}"+Placeholder)
+	s = jsonSecret.ReplaceAllString(s, `${1}"`+Placeholder+`"`)
+	s = bearer.ReplaceAllString(s, "${1}"+Placeholder)
+	return apiKey.ReplaceAllString(s, Placeholder)
+}
+
+// Bytes is like String for a byte slice, such as a response body.
+func Bytes(b []byte) []byte {
+	if len(b) == 0 {
+		return b
+	}
+	return []byte(String(string(b)))
+}
@@ -0,0 +1,209 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/websocket"
+)
+
+const (
+	defaultRetention = 15 * time.Minute
+	mebibyte         = 1024 * 1024
+)
+
+// Rule is a threshold rule evaluated against a server's limits, for example
+// "CPU above 90% of the limit for 5 minutes".
+type Rule struct {
+	// Name identifies the rule in alerts.
+	Name string
+	// Metric is the metric the rule applies to.
+	Metric Metric
+	// Threshold is the fraction of the server's limit (e.g. 0.9 for 90%) above
+	// which the condition is met.
+	Threshold float64
+	// For is how long the condition must hold before the alert fires.
+	For time.Duration
+}
+
+// Alert describes a rule that fired or resolved for a server.
+type Alert struct {
+	ServerID string
+	Rule     Rule
+	// Usage is the metric value as a fraction of the limit when the alert changed.
+	Usage float64
+	// Since is when the condition was first met.
+	Since time.Time
+	// Time is the time of the sample that changed the alert.
+	Time time.Time
+}
+
+// AggregatorOptions configures an Aggregator.
+type AggregatorOptions struct {
+	// Retention is how long samples are kept per server. Defaults to 15 minutes.
+	Retention time.Duration
+	// Rules are evaluated on every sample.
+	Rules []Rule
+	// OnFire is called when a rule's condition has held for its duration.
+	OnFire func(Alert)
+	// OnResolve is called when the condition of a firing rule is no longer met.
+	OnResolve func(Alert)
+}
+
+// ruleState tracks the evaluation of a single rule for a single server.
+type ruleState struct {
+	since  time.Time
+	firing bool
+}
+
+// serverSeries holds the samples, limits and rule states of a server.
+type serverSeries struct {
+	series *Series
+	limits *models.Limits
+	rules  []ruleState
+}
+
+// Aggregator keeps a Series per server and evaluates threshold rules against
+// the limits of each server. It is safe for concurrent use.
+type Aggregator struct {
+	opts    AggregatorOptions
+	mu      sync.Mutex
+	servers map[string]*serverSeries
+}
+
+// NewAggregator creates a new Aggregator.
+func NewAggregator(opts AggregatorOptions) *Aggregator {
+	if opts.Retention <= 0 {
+		opts.Retention = defaultRetention
+	}
+	return &Aggregator{opts: opts, servers: make(map[string]*serverSeries)}
+}
+
+// get returns the data for a server, creating it if necessary. a.mu must be held.
+func (a *Aggregator) get(serverID string) *serverSeries {
+	s, ok := a.servers[serverID]
+	if !ok {
+		s = &serverSeries{
+			series: NewSeries(a.opts.Retention),
+			rules:  make([]ruleState, len(a.opts.Rules)),
+		}
+		a.servers[serverID] = s
+	}
+	return s
+}
+
+// SetLimits sets the limits rules are evaluated against, typically taken from
+// GetServer. Without limits, only memory rules are evaluated, using the memory
+// limit reported in each sample.
+func (a *Aggregator) SetLimits(serverID string, limits models.Limits) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.get(serverID).limits = &limits
+}
+
+// Series returns the series of a server, or nil if no samples were observed.
+func (a *Aggregator) Series(serverID string) *Series {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if s, ok := a.servers[serverID]; ok {
+		return s.series
+	}
+	return nil
+}
+
+// Forget removes all data of a server.
+func (a *Aggregator) Forget(serverID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.servers, serverID)
+}
+
+// Watch observes StatsEvent values received on events until the channel is
+// closed or the context is done. Samples are timestamped on receipt.
+func (a *Aggregator) Watch(ctx context.Context, serverID string, events <-chan websocket.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if e, ok := event.(*websocket.StatsEvent); ok {
+				a.Observe(serverID, time.Now(), e.Stats)
+			}
+		}
+	}
+}
+
+// Observe records a sample and evaluates all rules for the server.
+func (a *Aggregator) Observe(serverID string, t time.Time, r models.Resources) {
+	var fired, resolved []Alert
+
+	a.mu.Lock()
+	s := a.get(serverID)
+	s.series.Add(t, r)
+	for i, rule := range a.opts.Rules {
+		usage, ok := usage(rule.Metric, r, s.limits)
+		state := &s.rules[i]
+		alert := Alert{ServerID: serverID, Rule: rule, Usage: usage, Since: state.since, Time: t}
+
+		if !ok || usage <= rule.Threshold {
+			if state.firing {
+				resolved = append(resolved, alert)
+			}
+			*state = ruleState{}
+			continue
+		}
+
+		if state.since.IsZero() {
+			state.since = t
+			alert.Since = t
+		}
+		if !state.firing && t.Sub(state.since) >= rule.For {
+			state.firing = true
+			fired = append(fired, alert)
+		}
+	}
+	a.mu.Unlock()
+
+	for _, alert := range fired {
+		if a.opts.OnFire != nil {
+			a.opts.OnFire(alert)
+		}
+	}
+	for _, alert := range resolved {
+		if a.opts.OnResolve != nil {
+			a.opts.OnResolve(alert)
+		}
+	}
+}
+
+// usage returns a metric value as a fraction of the server's limit. A limit of
+// zero means unlimited, in which case ok is false.
+func usage(m Metric, r models.Resources, limits *models.Limits) (float64, bool) {
+	var limit float64
+	switch m {
+	case MetricCPU:
+		if limits != nil {
+			limit = float64(limits.CPU)
+		}
+	case MetricMemory:
+		if limits != nil {
+			limit = float64(limits.Memory) * mebibyte
+		}
+		if limit <= 0 {
+			limit = float64(r.MemoryLimitBytes)
+		}
+	case MetricDisk:
+		if limits != nil {
+			limit = float64(limits.Disk) * mebibyte
+		}
+	}
+	if limit <= 0 {
+		return 0, false
+	}
+	return m.value(r) / limit, true
+}
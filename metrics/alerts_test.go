@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+func TestAggregator_FireAndResolve(t *testing.T) {
+	var fired, resolved []Alert
+	a := NewAggregator(AggregatorOptions{
+		Rules: []Rule{{Name: "cpu-high", Metric: MetricCPU, Threshold: 0.9, For: 5 * time.Minute}},
+		OnFire: func(alert Alert) {
+			fired = append(fired, alert)
+		},
+		OnResolve: func(alert Alert) {
+			resolved = append(resolved, alert)
+		},
+	})
+	a.SetLimits("abc", models.Limits{CPU: 200})
+
+	start := time.Unix(1000, 0)
+	for i := 0; i <= 6; i++ {
+		a.Observe("abc", start.Add(time.Duration(i)*time.Minute), models.Resources{CPUAbsolute: 190})
+	}
+	if len(fired) != 1 {
+		t.Fatalf("fired %d alerts, want 1", len(fired))
+	}
+	if got := fired[0]; got.Rule.Name != "cpu-high" || !got.Since.Equal(start) || !got.Time.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("unexpected alert: %+v", got)
+	}
+
+	a.Observe("abc", start.Add(7*time.Minute), models.Resources{CPUAbsolute: 100})
+	if len(resolved) != 1 || resolved[0].Usage != 0.5 {
+		t.Errorf("resolved = %+v, want one alert with usage 0.5", resolved)
+	}
+}
+
+func TestAggregator_InterruptedCondition(t *testing.T) {
+	var fired int
+	a := NewAggregator(AggregatorOptions{
+		Rules:  []Rule{{Metric: MetricMemory, Threshold: 0.8, For: 2 * time.Minute}},
+		OnFire: func(Alert) { fired++ },
+	})
+
+	// Without limits, the memory limit from the sample is used.
+	start := time.Unix(1000, 0)
+	a.Observe("abc", start, models.Resources{MemoryBytes: 90, MemoryLimitBytes: 100})
+	a.Observe("abc", start.Add(time.Minute), models.Resources{MemoryBytes: 10, MemoryLimitBytes: 100})
+	a.Observe("abc", start.Add(2*time.Minute), models.Resources{MemoryBytes: 90, MemoryLimitBytes: 100})
+	if fired != 0 {
+		t.Errorf("fired %d alerts, want 0", fired)
+	}
+
+	a.Observe("abc", start.Add(4*time.Minute), models.Resources{MemoryBytes: 90, MemoryLimitBytes: 100})
+	if fired != 1 {
+		t.Errorf("fired %d alerts, want 1", fired)
+	}
+}
+
+func TestAggregator_UnlimitedCPU(t *testing.T) {
+	var fired int
+	a := NewAggregator(AggregatorOptions{
+		Rules:  []Rule{{Metric: MetricCPU, Threshold: 0.5}},
+		OnFire: func(Alert) { fired++ },
+	})
+	a.SetLimits("abc", models.Limits{CPU: 0})
+	a.Observe("abc", time.Unix(1000, 0), models.Resources{CPUAbsolute: 400})
+	if fired != 0 {
+		t.Errorf("fired %d alerts for an unlimited server, want 0", fired)
+	}
+	if a.Series("abc").Len() != 1 {
+		t.Error("sample was not recorded")
+	}
+}
@@ -0,0 +1,300 @@
+// Package metrics collects server resource usage, aggregates it over time,
+// evaluates threshold alerts and exposes it in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/client"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/websocket"
+)
+
+// contentType is the media type of the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// states are the power states exposed by the server state metric.
+var states = []string{"offline", "starting", "running", "stopping"}
+
+// ServerInfo identifies a server in exported metrics.
+type ServerInfo struct {
+	// Identifier is the short server identifier used by the Client API.
+	Identifier string
+	// Name is the display name of the server.
+	Name string
+	// Node is the name of the node the server runs on.
+	Node string
+}
+
+// ServerInfoFrom builds a ServerInfo from a server returned by the Client API.
+func ServerInfoFrom(s *models.Server) ServerInfo {
+	return ServerInfo{Identifier: s.Identifier, Name: s.Name, Node: s.Node}
+}
+
+// sample is the latest known data for a single server.
+type sample struct {
+	info        ServerInfo
+	state       string
+	resources   models.Resources
+	updated     time.Time
+	pollErrors  int64
+	hasSnapshot bool
+}
+
+// Collector stores the latest resource sample per server and serves them as
+// Prometheus metrics. It is safe for concurrent use.
+type Collector struct {
+	mu      sync.RWMutex
+	servers map[string]*sample
+}
+
+// NewCollector creates a new, empty Collector.
+func NewCollector() *Collector {
+	return &Collector{servers: make(map[string]*sample)}
+}
+
+// get returns the sample for a server, creating it if necessary. c.mu must be held.
+func (c *Collector) get(info ServerInfo) *sample {
+	s, ok := c.servers[info.Identifier]
+	if !ok {
+		s = &sample{}
+		c.servers[info.Identifier] = s
+	}
+	s.info = info
+	return s
+}
+
+// Observe records a resource sample for a server. An empty state leaves the
+// previously known state unchanged.
+func (c *Collector) Observe(info ServerInfo, state string, resources models.Resources) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.get(info)
+	if state != "" {
+		s.state = state
+	}
+	s.resources = resources
+	s.updated = time.Now()
+	s.hasSnapshot = true
+}
+
+// ObserveState records a power state change for a server.
+func (c *Collector) ObserveState(info ServerInfo, state string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.get(info)
+	s.state = state
+	s.updated = time.Now()
+}
+
+// Forget removes a server from the collector.
+func (c *Collector) Forget(identifier string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.servers, identifier)
+}
+
+// Watch records StatsEvent and StatusEvent values received on events until the
+// channel is closed or the context is done. Other events are ignored.
+func (c *Collector) Watch(ctx context.Context, info ServerInfo, events <-chan websocket.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			switch e := event.(type) {
+			case *websocket.StatsEvent:
+				c.Observe(info, "", e.Stats)
+			case *websocket.StatusEvent:
+				c.ObserveState(info, e.Status)
+			}
+		}
+	}
+}
+
+// Poll fetches resources for every server with GetServerResources once per
+// interval until the context is done. Failed requests are counted in the
+// pterodactyl_server_poll_errors_total metric and do not stop polling.
+func (c *Collector) Poll(ctx context.Context, cl client.ClientClient, servers []ServerInfo, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("poll interval must be positive, got %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.PollOnce(ctx, cl, servers)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollOnce fetches resources for every server concurrently and records them.
+func (c *Collector) PollOnce(ctx context.Context, cl client.ClientClient, servers []ServerInfo) {
+	var wg sync.WaitGroup
+	for _, info := range servers {
+		wg.Add(1)
+		go func(info ServerInfo) {
+			defer wg.Done()
+			stats, err := cl.GetServerResources(ctx, info.Identifier)
+			if err != nil {
+				c.mu.Lock()
+				c.get(info).pollErrors++
+				c.mu.Unlock()
+				return
+			}
+			c.Observe(info, stats.CurrentState, stats.Resources)
+		}(info)
+	}
+	wg.Wait()
+}
+
+// ServeHTTP writes all metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	c.WriteTo(w)
+}
+
+// metric describes a single exported metric family.
+type metric struct {
+	name  string
+	help  string
+	kind  string
+	value func(s *sample) float64
+}
+
+var resourceMetrics = []metric{
+	{"pterodactyl_server_cpu_percent", "CPU usage in percent of a single core.", "gauge",
+		func(s *sample) float64 { return s.resources.CPUAbsolute }},
+	{"pterodactyl_server_memory_bytes", "Memory usage in bytes.", "gauge",
+		func(s *sample) float64 { return float64(s.resources.MemoryBytes) }},
+	{"pterodactyl_server_memory_limit_bytes", "Memory limit in bytes.", "gauge",
+		func(s *sample) float64 { return float64(s.resources.MemoryLimitBytes) }},
+	{"pterodactyl_server_disk_bytes", "Disk usage in bytes.", "gauge",
+		func(s *sample) float64 { return float64(s.resources.DiskBytes) }},
+	{"pterodactyl_server_network_receive_bytes_total", "Bytes received since the server started.", "counter",
+		func(s *sample) float64 { return float64(s.resources.NetworkRxBytes) }},
+	{"pterodactyl_server_network_transmit_bytes_total", "Bytes transmitted since the server started.", "counter",
+		func(s *sample) float64 { return float64(s.resources.NetworkTxBytes) }},
+	{"pterodactyl_server_uptime_seconds", "Time since the server started in seconds.", "gauge",
+		func(s *sample) float64 { return float64(s.resources.Uptime) / 1000 }},
+	{"pterodactyl_server_last_update_timestamp_seconds", "Unix time of the last received sample.", "gauge",
+		func(s *sample) float64 { return float64(s.updated.UnixNano()) / 1e9 }},
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format to w.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.RLock()
+	samples := make([]sample, 0, len(c.servers))
+	for _, s := range c.servers {
+		samples = append(samples, *s)
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].info.Identifier < samples[j].info.Identifier
+	})
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	writeHeader(cw, "pterodactyl_server_state", "Current power state of the server.", "gauge")
+	for i := range samples {
+		s := &samples[i]
+		if s.state == "" {
+			continue
+		}
+		for _, state := range states {
+			value := 0.0
+			if s.state == state {
+				value = 1
+			}
+			writeSample(cw, "pterodactyl_server_state", labels(s.info, "state", state), value)
+		}
+	}
+
+	for _, m := range resourceMetrics {
+		writeHeader(cw, m.name, m.help, m.kind)
+		for i := range samples {
+			if s := &samples[i]; s.hasSnapshot {
+				writeSample(cw, m.name, labels(s.info), m.value(s))
+			}
+		}
+	}
+
+	writeHeader(cw, "pterodactyl_server_poll_errors_total", "Failed resource polls.", "counter")
+	for i := range samples {
+		s := &samples[i]
+		writeSample(cw, "pterodactyl_server_poll_errors_total", labels(s.info), float64(s.pollErrors))
+	}
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// countingWriter keeps track of bytes written and the first error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) printf(format string, args ...interface{}) {
+	if cw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.err = err
+}
+
+func writeHeader(cw *countingWriter, name, help, kind string) {
+	cw.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeSample(cw *countingWriter, name, labels string, value float64) {
+	cw.printf("%s{%s} %s\n", name, labels, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// labels formats the server labels and any extra name/value pairs.
+func labels(info ServerInfo, extra ...string) string {
+	pairs := []string{
+		"server", info.Identifier,
+		"name", info.Name,
+		"node", info.Node,
+	}
+	pairs = append(pairs, extra...)
+
+	var sb strings.Builder
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(pairs[i])
+		sb.WriteString(`="`)
+		sb.WriteString(labelEscaper.Replace(pairs[i+1]))
+		sb.WriteByte('"')
+	}
+	return sb.String()
+}
+
+// labelEscaper escapes label values as required by the text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
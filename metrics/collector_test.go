@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/idanyas/go-pterodactyl/client"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/websocket"
+)
+
+// resourcesClient implements only GetServerResources; other methods panic.
+type resourcesClient struct {
+	client.ClientClient
+	stats map[string]*models.Stats
+}
+
+func (c *resourcesClient) GetServerResources(ctx context.Context, serverID string) (*models.Stats, error) {
+	if s, ok := c.stats[serverID]; ok {
+		return s, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	c := NewCollector()
+	info := ServerInfo{Identifier: "abc", Name: `My "Server"`, Node: "node-1"}
+	c.Observe(info, "running", models.Resources{
+		MemoryBytes:      1024,
+		MemoryLimitBytes: 2048,
+		CPUAbsolute:      12.5,
+		NetworkRxBytes:   100,
+		Uptime:           1500,
+	})
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != contentType {
+		t.Errorf("Content-Type = %q, want %q", got, contentType)
+	}
+
+	body := rec.Body.String()
+	labels := `server="abc",name="My \"Server\"",node="node-1"`
+	for _, want := range []string{
+		"# TYPE pterodactyl_server_memory_bytes gauge\n",
+		"pterodactyl_server_memory_bytes{" + labels + "} 1024\n",
+		"pterodactyl_server_cpu_percent{" + labels + "} 12.5\n",
+		"# TYPE pterodactyl_server_network_receive_bytes_total counter\n",
+		"pterodactyl_server_network_receive_bytes_total{" + labels + "} 100\n",
+		"pterodactyl_server_uptime_seconds{" + labels + "} 1.5\n",
+		"pterodactyl_server_state{" + labels + `,state="running"} 1` + "\n",
+		"pterodactyl_server_state{" + labels + `,state="offline"} 0` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("output does not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestCollector_Watch(t *testing.T) {
+	c := NewCollector()
+	info := ServerInfo{Identifier: "abc"}
+
+	events := make(chan websocket.Event, 3)
+	events <- &websocket.StatusEvent{Status: "starting"}
+	events <- &websocket.StatsEvent{Stats: models.Resources{DiskBytes: 42}}
+	events <- &websocket.ConsoleOutputEvent{Line: "ignored"}
+	close(events)
+
+	c.Watch(context.Background(), info, events)
+
+	var sb strings.Builder
+	if _, err := c.WriteTo(&sb); err != nil {
+		t.Fatalf("WriteTo() returned error: %v", err)
+	}
+	out := sb.String()
+	if !strings.Contains(out, `pterodactyl_server_disk_bytes{server="abc",name="",node=""} 42`) {
+		t.Errorf("disk sample missing:\n%s", out)
+	}
+	if !strings.Contains(out, `state="starting"} 1`) {
+		t.Errorf("state sample missing:\n%s", out)
+	}
+}
+
+func TestCollector_PollOnce(t *testing.T) {
+	c := NewCollector()
+	cl := &resourcesClient{stats: map[string]*models.Stats{
+		"abc": {CurrentState: "offline", Resources: models.Resources{MemoryBytes: 7}},
+	}}
+
+	c.PollOnce(context.Background(), cl, []ServerInfo{{Identifier: "abc"}, {Identifier: "missing"}})
+
+	var sb strings.Builder
+	c.WriteTo(&sb)
+	out := sb.String()
+	if !strings.Contains(out, `pterodactyl_server_memory_bytes{server="abc",name="",node=""} 7`) {
+		t.Errorf("memory sample missing:\n%s", out)
+	}
+	if !strings.Contains(out, `pterodactyl_server_poll_errors_total{server="missing",name="",node=""} 1`) {
+		t.Errorf("poll error missing:\n%s", out)
+	}
+	if strings.Contains(out, `pterodactyl_server_memory_bytes{server="missing"`) {
+		t.Errorf("server without samples should not export resources:\n%s", out)
+	}
+}
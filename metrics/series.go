@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+// Metric selects a value from a resource sample.
+type Metric string
+
+const (
+	// MetricCPU is the CPU usage in percent of a single core.
+	MetricCPU Metric = "cpu"
+	// MetricMemory is the memory usage in bytes.
+	MetricMemory Metric = "memory"
+	// MetricDisk is the disk usage in bytes.
+	MetricDisk Metric = "disk"
+)
+
+// value returns the metric value of a resource sample.
+func (m Metric) value(r models.Resources) float64 {
+	switch m {
+	case MetricCPU:
+		return r.CPUAbsolute
+	case MetricMemory:
+		return float64(r.MemoryBytes)
+	case MetricDisk:
+		return float64(r.DiskBytes)
+	}
+	return 0
+}
+
+// Sample is a single resource usage sample.
+type Sample struct {
+	Time      time.Time
+	Resources models.Resources
+}
+
+// Series is a time-ordered buffer of resource samples for a single server.
+// Samples older than the retention period are discarded as new ones arrive.
+// Windows are measured back from the latest sample. It is safe for concurrent use.
+type Series struct {
+	mu        sync.RWMutex
+	retention time.Duration
+	samples   []Sample
+}
+
+// NewSeries creates a new Series keeping samples for the given retention period.
+func NewSeries(retention time.Duration) *Series {
+	return &Series{retention: retention}
+}
+
+// Add appends a sample. Samples older than the latest one are ignored.
+func (s *Series) Add(t time.Time, r models.Resources) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n := len(s.samples); n > 0 && t.Before(s.samples[n-1].Time) {
+		return
+	}
+	s.samples = append(s.samples, Sample{Time: t, Resources: r})
+
+	cutoff := t.Add(-s.retention)
+	i := 0
+	for i < len(s.samples) && s.samples[i].Time.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		s.samples = append(s.samples[:0], s.samples[i:]...)
+	}
+}
+
+// Len returns the number of samples in the series.
+func (s *Series) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.samples)
+}
+
+// Latest returns the most recent sample.
+func (s *Series) Latest() (Sample, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.samples) == 0 {
+		return Sample{}, false
+	}
+	return s.samples[len(s.samples)-1], true
+}
+
+// window returns the samples within d of the latest sample. s.mu must be held.
+func (s *Series) window(d time.Duration) []Sample {
+	if len(s.samples) == 0 {
+		return nil
+	}
+	cutoff := s.samples[len(s.samples)-1].Time.Add(-d)
+	i := sort.Search(len(s.samples), func(i int) bool {
+		return !s.samples[i].Time.Before(cutoff)
+	})
+	return s.samples[i:]
+}
+
+// Average returns the mean value of a metric over the window.
+func (s *Series) Average(m Metric, d time.Duration) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := s.window(d)
+	if len(samples) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, sample := range samples {
+		sum += m.value(sample.Resources)
+	}
+	return sum / float64(len(samples)), true
+}
+
+// Percentile returns the p-th percentile (0-100) of a metric over the window,
+// using the nearest-rank method.
+func (s *Series) Percentile(m Metric, p float64, d time.Duration) (float64, bool) {
+	s.mu.RLock()
+	samples := s.window(d)
+	values := make([]float64, len(samples))
+	for i, sample := range samples {
+		values[i] = m.value(sample.Resources)
+	}
+	s.mu.RUnlock()
+
+	if len(values) == 0 {
+		return 0, false
+	}
+	sort.Float64s(values)
+
+	rank := int(math.Ceil(p / 100 * float64(len(values))))
+	if rank < 1 {
+		rank = 1
+	} else if rank > len(values) {
+		rank = len(values)
+	}
+	return values[rank-1], true
+}
+
+// NetworkRate returns the average receive and transmit rates in bytes per second
+// over the window, computed from the cumulative network counters. Counter resets,
+// such as after a server restart, are detected and do not produce negative rates.
+func (s *Series) NetworkRate(d time.Duration) (rx, tx float64, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := s.window(d)
+	if len(samples) < 2 {
+		return 0, 0, false
+	}
+
+	var rxBytes, txBytes int64
+	for i := 1; i < len(samples); i++ {
+		prev, cur := samples[i-1].Resources, samples[i].Resources
+		rxBytes += counterDelta(prev.NetworkRxBytes, cur.NetworkRxBytes)
+		txBytes += counterDelta(prev.NetworkTxBytes, cur.NetworkTxBytes)
+	}
+
+	elapsed := samples[len(samples)-1].Time.Sub(samples[0].Time).Seconds()
+	if elapsed <= 0 {
+		return 0, 0, false
+	}
+	return float64(rxBytes) / elapsed, float64(txBytes) / elapsed, true
+}
+
+// counterDelta returns the increase of a cumulative counter, treating a
+// decrease as a reset to zero.
+func counterDelta(prev, cur int64) int64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
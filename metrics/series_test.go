@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+func TestSeries_Retention(t *testing.T) {
+	s := NewSeries(time.Minute)
+	start := time.Unix(1000, 0)
+	for i := 0; i < 10; i++ {
+		s.Add(start.Add(time.Duration(i)*20*time.Second), models.Resources{})
+	}
+	// Samples at 120s..180s are within a minute of the latest sample.
+	if got := s.Len(); got != 4 {
+		t.Errorf("Len() = %d, want 4", got)
+	}
+}
+
+func TestSeries_AverageAndPercentile(t *testing.T) {
+	s := NewSeries(time.Hour)
+	start := time.Unix(1000, 0)
+	for i := 1; i <= 20; i++ {
+		s.Add(start.Add(time.Duration(i)*time.Second), models.Resources{CPUAbsolute: float64(i)})
+	}
+
+	if avg, ok := s.Average(MetricCPU, time.Hour); !ok || avg != 10.5 {
+		t.Errorf("Average() = %v, %v, want 10.5, true", avg, ok)
+	}
+	if avg, ok := s.Average(MetricCPU, 3*time.Second); !ok || avg != 18.5 {
+		t.Errorf("Average(3s) = %v, %v, want 18.5, true", avg, ok)
+	}
+	if p95, ok := s.Percentile(MetricCPU, 95, time.Hour); !ok || p95 != 19 {
+		t.Errorf("Percentile(95) = %v, %v, want 19, true", p95, ok)
+	}
+	if _, ok := NewSeries(time.Minute).Average(MetricCPU, time.Minute); ok {
+		t.Error("Average() on empty series should not be ok")
+	}
+}
+
+func TestSeries_NetworkRate(t *testing.T) {
+	s := NewSeries(time.Hour)
+	start := time.Unix(1000, 0)
+	s.Add(start, models.Resources{NetworkRxBytes: 1000, NetworkTxBytes: 500})
+	s.Add(start.Add(10*time.Second), models.Resources{NetworkRxBytes: 2000, NetworkTxBytes: 1500})
+	// Counter reset after a restart.
+	s.Add(start.Add(20*time.Second), models.Resources{NetworkRxBytes: 1000, NetworkTxBytes: 0})
+
+	rx, tx, ok := s.NetworkRate(time.Hour)
+	if !ok {
+		t.Fatal("NetworkRate() not ok")
+	}
+	if rx != 100 || tx != 50 {
+		t.Errorf("NetworkRate() = %v, %v, want 100, 50", rx, tx)
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/idanyas/go-pterodactyl/internal/redact"
+)
+
+// The types in this file hold credentials. Their String and LogValue methods
+// redact them, so printing or logging a value does not leak the secret. The
+// fields themselves are left intact.
+
+// String returns the key with its secret token redacted.
+func (k APIKey) String() string {
+	type plain APIKey
+	p := plain(k)
+	p.Meta.SecretToken = redact.Secret(p.Meta.SecretToken)
+	return fmt.Sprintf("%+v", p)
+}
+
+// LogValue implements slog.LogValuer, redacting the secret token.
+func (k APIKey) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("identifier", k.Identifier),
+		slog.String("description", k.Description),
+		slog.String("secret_token", redact.Secret(k.Meta.SecretToken)),
+	)
+}
+
+// String returns the password redacted.
+func (p DatabasePassword) String() string {
+	return fmt.Sprintf("{Password:%s}", redact.Secret(p.Password))
+}
+
+// LogValue implements slog.LogValuer, redacting the password.
+func (p DatabasePassword) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("password", redact.Secret(p.Password)))
+}
+
+// String returns the configuration with the node token redacted.
+func (c NodeConfiguration) String() string {
+	type plain NodeConfiguration
+	p := plain(c)
+	p.Token = redact.Secret(p.Token)
+	return fmt.Sprintf("%+v", p)
+}
+
+// LogValue implements slog.LogValuer, redacting the node token.
+func (c NodeConfiguration) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("uuid", c.UUID),
+		slog.String("token_id", c.TokenID),
+		slog.String("token", redact.Secret(c.Token)),
+		slog.String("remote", c.Remote),
+	)
+}
+
+// String returns the URL with its token redacted.
+func (u SignedURL) String() string {
+	return fmt.Sprintf("{URL:%s}", redact.URL(u.URL))
+}
+
+// LogValue implements slog.LogValuer, redacting the URL token.
+func (u SignedURL) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("url", redact.URL(u.URL)))
+}
+
+// String returns the 2FA data with the secret redacted, including the copy
+// in the otpauth URL.
+func (d TwoFactorData) String() string {
+	return fmt.Sprintf("{ImageURLData:%s Secret:%s}", redact.URL(d.ImageURLData), redact.Secret(d.Secret))
+}
+
+// LogValue implements slog.LogValuer, redacting the secret.
+func (d TwoFactorData) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("secret", redact.Secret(d.Secret)))
+}
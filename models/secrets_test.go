@@ -0,0 +1,50 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSecretsAreRedacted(t *testing.T) {
+	key := APIKey{Identifier: "ptlc_1234567890a"}
+	key.Meta.SecretToken = "ptlc_supersecret"
+	db := Database{Name: "s1_db"}
+	db.Password = &struct {
+		Attributes DatabasePassword `json:"attributes"`
+	}{DatabasePassword{Password: "db-password"}}
+
+	values := []any{
+		key,
+		&key,
+		db,
+		NodeConfiguration{UUID: "node-uuid", Token: "node-token"},
+		SignedURL{URL: "https://node.example.com/download?token=signed-jwt"},
+		TwoFactorData{ImageURLData: "otpauth://totp/x?secret=totp-secret", Secret: "totp-secret"},
+	}
+	secrets := []string{"ptlc_supersecret", "db-password", "node-token", "signed-jwt", "totp-secret"}
+
+	for _, v := range values {
+		var logged bytes.Buffer
+		slog.New(slog.NewTextHandler(&logged, nil)).Info("value", "v", v)
+		for _, out := range []string{fmt.Sprint(v), fmt.Sprintf("%+v", v), logged.String()} {
+			for _, secret := range secrets {
+				if strings.Contains(out, secret) {
+					t.Errorf("%T output contains %q: %s", v, secret, out)
+				}
+			}
+		}
+	}
+	if !strings.Contains(fmt.Sprint(key), "ptlc_1234567890a") {
+		t.Errorf("String() dropped the identifier: %s", key)
+	}
+
+	// Encoding is unaffected.
+	data, _ := json.Marshal(key)
+	if !strings.Contains(string(data), "ptlc_supersecret") {
+		t.Errorf("JSON encoding lost the secret token: %s", data)
+	}
+}
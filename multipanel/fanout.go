@@ -0,0 +1,149 @@
+package multipanel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/idanyas/go-pterodactyl"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/pagination"
+)
+
+// Tagged is a value returned by one panel.
+type Tagged[T any] struct {
+	// Panel is the name of the panel the value came from.
+	Panel string
+	Value T
+}
+
+// PanelError is an error returned by one panel.
+type PanelError struct {
+	Panel string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *PanelError) Error() string {
+	return fmt.Sprintf("panel %s: %v", e.Panel, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PanelError) Unwrap() error {
+	return e.Err
+}
+
+// Errors holds the errors of the panels that failed during a fan-out call.
+// Results from the other panels are still returned alongside it.
+type Errors []*PanelError
+
+// Error implements the error interface.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d panel(s) failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the per-panel errors, so errors.Is and errors.As match any
+// of them.
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Panel returns the error of the named panel, or nil if it succeeded.
+func (e Errors) Panel(name string) error {
+	for _, err := range e {
+		if err.Panel == name {
+			return err.Err
+		}
+	}
+	return nil
+}
+
+// FanOut calls fn for every registered panel concurrently and returns the
+// combined results tagged by panel, in panel name order. If some panels fail,
+// the results of the others are returned together with an Errors value
+// describing each failure.
+func FanOut[T any](ctx context.Context, r *Registry, fn func(ctx context.Context, name string, c *pterodactyl.Client) ([]T, error)) ([]Tagged[T], error) {
+	panels := r.snapshot()
+	results := make([][]T, len(panels))
+	errs := make([]error, len(panels))
+
+	var wg sync.WaitGroup
+	for i, p := range panels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = fn(ctx, p.name, p.client)
+		}()
+	}
+	wg.Wait()
+
+	var tagged []Tagged[T]
+	var failed Errors
+	for i, p := range panels {
+		if errs[i] != nil {
+			failed = append(failed, &PanelError{Panel: p.name, Err: errs[i]})
+			continue
+		}
+		for _, v := range results[i] {
+			tagged = append(tagged, Tagged[T]{Panel: p.name, Value: v})
+		}
+	}
+	if failed != nil {
+		return tagged, failed
+	}
+	return tagged, nil
+}
+
+// ListServers lists the servers of every panel through the Application API.
+func (r *Registry) ListServers(ctx context.Context) ([]Tagged[*models.Server], error) {
+	return FanOut(ctx, r, func(ctx context.Context, _ string, c *pterodactyl.Client) ([]*models.Server, error) {
+		return all(ctx, c.Application().ListServers)
+	})
+}
+
+// ListUsers lists the users of every panel through the Application API.
+func (r *Registry) ListUsers(ctx context.Context) ([]Tagged[*models.User], error) {
+	return FanOut(ctx, r, func(ctx context.Context, _ string, c *pterodactyl.Client) ([]*models.User, error) {
+		return all(ctx, c.Application().ListUsers)
+	})
+}
+
+// ListNodes lists the nodes of every panel through the Application API.
+func (r *Registry) ListNodes(ctx context.Context) ([]Tagged[*models.Node], error) {
+	return FanOut(ctx, r, func(ctx context.Context, _ string, c *pterodactyl.Client) ([]*models.Node, error) {
+		return all(ctx, c.Application().ListNodes)
+	})
+}
+
+// ListClientServers lists the servers the Client API key of every panel has
+// access to.
+func (r *Registry) ListClientServers(ctx context.Context) ([]Tagged[*models.Server], error) {
+	return FanOut(ctx, r, func(ctx context.Context, _ string, c *pterodactyl.Client) ([]*models.Server, error) {
+		return all(ctx, c.Client().ListServers)
+	})
+}
+
+// all fetches every page of a paginated list.
+func all[T any](ctx context.Context, list func(context.Context, pagination.ListOptions) ([]T, *pagination.Paginator[T], error)) ([]T, error) {
+	items, p, err := list(ctx, pagination.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+	}
+	return items, nil
+}
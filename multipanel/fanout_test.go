@@ -0,0 +1,100 @@
+package multipanel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/idanyas/go-pterodactyl"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/ptest"
+)
+
+// newPanel starts a fake panel with servers of the given names and registers
+// it under name.
+func newPanel(t *testing.T, r *Registry, name string, servers ...string) *ptest.Panel {
+	t.Helper()
+	p := ptest.NewPanel()
+	t.Cleanup(p.Close)
+	for _, s := range servers {
+		p.AddServer(models.Server{Name: s})
+	}
+
+	c, err := p.Client(pterodactyl.WithMaxRetries(1))
+	if err != nil {
+		t.Fatalf("Client() error = %v", err)
+	}
+	if err := r.Register(name, c); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return p
+}
+
+func TestRegistry_ListServers(t *testing.T) {
+	r := NewRegistry()
+	newPanel(t, r, "us", "Survival")
+	newPanel(t, r, "eu", "Lobby", "Creative")
+
+	servers, err := r.ListServers(context.Background())
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+
+	var got []string
+	for _, s := range servers {
+		got = append(got, s.Panel+"/"+s.Value.Name)
+	}
+	want := []string{"eu/Lobby", "eu/Creative", "us/Survival"}
+	if len(got) != len(want) {
+		t.Fatalf("ListServers() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ListServers()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRegistry_ListServers_PartialFailure(t *testing.T) {
+	r := NewRegistry()
+	newPanel(t, r, "eu", "Lobby")
+	down := newPanel(t, r, "us", "Survival")
+	down.InjectFault(&ptest.Fault{Status: http.StatusServiceUnavailable})
+
+	servers, err := r.ListServers(context.Background())
+	if len(servers) != 1 || servers[0].Panel != "eu" {
+		t.Errorf("ListServers() = %v, want the servers of eu", servers)
+	}
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("ListServers() error = %v, want Errors", err)
+	}
+	if len(errs) != 1 || errs[0].Panel != "us" {
+		t.Errorf("Errors = %v, want a single error for us", errs)
+	}
+	if errs.Panel("us") == nil || errs.Panel("eu") != nil {
+		t.Errorf("Errors.Panel() does not match the failed panel: %v", errs)
+	}
+	var apiErr *pterodactyl.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("errors.As(APIError) = %v, want the 503 of us", apiErr)
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	r := NewRegistry()
+	newPanel(t, r, "eu")
+	newPanel(t, r, "us")
+
+	results, err := FanOut(context.Background(), r, func(ctx context.Context, name string, c *pterodactyl.Client) ([]string, error) {
+		return []string{name + "-a", name + "-b"}, nil
+	})
+	if err != nil {
+		t.Fatalf("FanOut() error = %v", err)
+	}
+	if len(results) != 4 || results[0].Value != "eu-a" || results[3].Value != "us-b" {
+		t.Errorf("FanOut() = %v, want results in panel order", results)
+	}
+}
@@ -0,0 +1,99 @@
+// Package multipanel manages clients for several Pterodactyl panels and runs
+// requests across all of them.
+package multipanel
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/idanyas/go-pterodactyl"
+)
+
+// Registry holds one client per panel, keyed by panel name. Each client keeps
+// its own base URL, API keys and transport settings. A Registry is safe for
+// concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	panels map[string]*pterodactyl.Client
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{panels: make(map[string]*pterodactyl.Client)}
+}
+
+// Add creates a client for the panel at panelURL and registers it under name.
+func (r *Registry) Add(name, panelURL string, opts ...pterodactyl.Option) (*pterodactyl.Client, error) {
+	c, err := pterodactyl.New(panelURL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for panel %q: %w", name, err)
+	}
+	if err := r.Register(name, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Register registers an existing client under name.
+func (r *Registry) Register(name string, c *pterodactyl.Client) error {
+	if name == "" {
+		return fmt.Errorf("panel name cannot be empty")
+	}
+	if c == nil {
+		return fmt.Errorf("client for panel %q cannot be nil", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.panels[name]; ok {
+		return fmt.Errorf("panel %q is already registered", name)
+	}
+	r.panels[name] = c
+	return nil
+}
+
+// Remove unregisters a panel. Removing an unknown panel is a no-op.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.panels, name)
+}
+
+// Get returns the client of a panel.
+func (r *Registry) Get(name string) (*pterodactyl.Client, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.panels[name]
+	return c, ok
+}
+
+// Names returns the names of all registered panels in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.panels))
+	for name := range r.panels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// snapshot returns the registered panels in name order.
+func (r *Registry) snapshot() []panel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	panels := make([]panel, 0, len(r.panels))
+	for name, c := range r.panels {
+		panels = append(panels, panel{name: name, client: c})
+	}
+	sort.Slice(panels, func(i, j int) bool { return panels[i].name < panels[j].name })
+	return panels
+}
+
+// panel is a registered client and its name.
+type panel struct {
+	name   string
+	client *pterodactyl.Client
+}
@@ -0,0 +1,44 @@
+package multipanel
+
+import (
+	"testing"
+
+	"github.com/idanyas/go-pterodactyl"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	eu, err := r.Add("eu", "https://eu.panel.example.com", pterodactyl.WithAPIKey("ptla_eu"))
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := r.Add("us", "https://us.panel.example.com", pterodactyl.WithAPIKey("ptla_us")); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if got, ok := r.Get("eu"); !ok || got != eu {
+		t.Errorf("Get(eu) = %v, %v, want the registered client", got, ok)
+	}
+	if _, err := r.Add("eu", "https://other.example.com"); err == nil {
+		t.Error("Add() with a duplicate name should return an error")
+	}
+	if _, err := r.Add("bad", ""); err == nil {
+		t.Error("Add() with an empty URL should return an error")
+	}
+	if err := r.Register("", eu); err == nil {
+		t.Error("Register() with an empty name should return an error")
+	}
+
+	if got := r.Names(); len(got) != 2 || got[0] != "eu" || got[1] != "us" {
+		t.Errorf("Names() = %v, want [eu us]", got)
+	}
+
+	r.Remove("eu")
+	if _, ok := r.Get("eu"); ok {
+		t.Error("Get(eu) found a removed panel")
+	}
+	if got := r.Names(); len(got) != 1 {
+		t.Errorf("Names() = %v after Remove, want [us]", got)
+	}
+}
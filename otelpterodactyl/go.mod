@@ -0,0 +1,26 @@
+module github.com/idanyas/go-pterodactyl/otelpterodactyl
+
+go 1.25.1
+
+require (
+	github.com/idanyas/go-pterodactyl v0.0.0
+	go.opentelemetry.io/otel v1.43.0
+	go.opentelemetry.io/otel/metric v1.43.0
+	go.opentelemetry.io/otel/sdk v1.43.0
+	go.opentelemetry.io/otel/sdk/metric v1.43.0
+	go.opentelemetry.io/otel/trace v1.43.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
+	golang.org/x/sys v0.42.0 // indirect
+)
+
+// Development only: build against the client in the parent directory.
+// Modules that depend on this one ignore the replace directive, so a release
+// of this module must require a tagged release of the client instead of v0.0.0.
+replace github.com/idanyas/go-pterodactyl => ../
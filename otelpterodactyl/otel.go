@@ -0,0 +1,198 @@
+// Package otelpterodactyl traces and measures go-pterodactyl requests with
+// OpenTelemetry. It is a separate module so that the client does not depend on
+// OpenTelemetry.
+//
+//	hooks, err := otelpterodactyl.Hooks()
+//	if err != nil {
+//		return err
+//	}
+//	c, err := pterodactyl.New(panelURL, pterodactyl.WithAPIKey(key), pterodactyl.WithHooks(hooks))
+//
+// Each API call becomes a client span named after the library method that sent
+// it, such as "application.CreateServer", with its retries recorded as span
+// events. Calls are measured by the pterodactyl.client.request.duration and
+// pterodactyl.client.request.retries histograms.
+package otelpterodactyl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/idanyas/go-pterodactyl/otelpterodactyl"
+
+// Attribute keys set on spans and metrics.
+const (
+	// OperationKey is the library method that sent the request.
+	OperationKey = attribute.Key("pterodactyl.operation")
+	// ServerIDKey is the server the request is about. It is only set on spans,
+	// to keep the cardinality of metrics low.
+	ServerIDKey = attribute.Key("pterodactyl.server.id")
+
+	methodKey    = attribute.Key("http.request.method")
+	pathKey      = attribute.Key("url.path")
+	statusKey    = attribute.Key("http.response.status_code")
+	resendKey    = attribute.Key("http.request.resend_count")
+	errorTypeKey = attribute.Key("error.type")
+	retryReason  = attribute.Key("pterodactyl.retry.reason")
+	retryAttempt = attribute.Key("pterodactyl.retry.attempt")
+	retryWaitKey = attribute.Key("pterodactyl.retry.wait_seconds")
+)
+
+// Option configures Hooks.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the tracer provider. Defaults to the global one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider. Defaults to the global one.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// WithPropagators sets the propagators used to add trace context headers to
+// requests. Defaults to the global ones.
+func WithPropagators(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = p
+	}
+}
+
+// Hooks returns transport hooks that trace and measure API calls. Pass them to
+// pterodactyl.WithHooks or transport.WithHooks.
+func Hooks(opts ...Option) (transport.Hooks, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	duration, err := meter.Float64Histogram("pterodactyl.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of API calls, including retries."))
+	if err != nil {
+		return transport.Hooks{}, fmt.Errorf("failed to create duration histogram: %w", err)
+	}
+	retries, err := meter.Int64Histogram("pterodactyl.client.request.retries",
+		metric.WithUnit("{retry}"),
+		metric.WithDescription("Number of retries of API calls."),
+		metric.WithExplicitBucketBoundaries(0, 1, 2, 3, 5, 10))
+	if err != nil {
+		return transport.Hooks{}, fmt.Errorf("failed to create retries histogram: %w", err)
+	}
+
+	h := &hooks{
+		tracer:      cfg.tracerProvider.Tracer(ScopeName),
+		propagators: cfg.propagators,
+		duration:    duration,
+		retries:     retries,
+	}
+	return transport.Hooks{
+		BeforeRequest: h.beforeRequest,
+		Inject:        h.inject,
+		OnRetry:       h.onRetry,
+		AfterResponse: h.afterResponse,
+	}, nil
+}
+
+type hooks struct {
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+	duration    metric.Float64Histogram
+	retries     metric.Int64Histogram
+}
+
+func (h *hooks) beforeRequest(ctx context.Context, info transport.RequestInfo) context.Context {
+	attrs := []attribute.KeyValue{methodKey.String(info.Method), pathKey.String(info.Path)}
+	if info.Operation != "" {
+		attrs = append(attrs, OperationKey.String(info.Operation))
+	}
+	if info.ServerID != "" {
+		attrs = append(attrs, ServerIDKey.String(info.ServerID))
+	}
+	ctx, _ = h.tracer.Start(ctx, spanName(info),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	return ctx
+}
+
+func (h *hooks) inject(ctx context.Context, header http.Header) {
+	h.propagators.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+func (h *hooks) onRetry(ctx context.Context, info transport.RequestInfo, attempt int, reason string, wait time.Duration) {
+	trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+		retryAttempt.Int(attempt),
+		retryReason.String(reason),
+		retryWaitKey.Float64(wait.Seconds()),
+	))
+}
+
+func (h *hooks) afterResponse(ctx context.Context, info transport.RequestInfo, result transport.Result) {
+	span := trace.SpanFromContext(ctx)
+	attrs := []attribute.KeyValue{methodKey.String(info.Method)}
+	if info.Operation != "" {
+		attrs = append(attrs, OperationKey.String(info.Operation))
+	}
+
+	var outcome []attribute.KeyValue
+	switch {
+	case result.Err != nil:
+		outcome = append(outcome, errorTypeKey.String(fmt.Sprintf("%T", result.Err)))
+		span.RecordError(result.Err)
+		span.SetStatus(codes.Error, result.Err.Error())
+	case result.Response != nil:
+		code := result.Response.StatusCode
+		outcome = append(outcome, statusKey.Int(code))
+		if code >= http.StatusBadRequest {
+			outcome = append(outcome, errorTypeKey.String(strconv.Itoa(code)))
+			span.SetStatus(codes.Error, http.StatusText(code))
+		}
+	}
+	attrs = append(attrs, outcome...)
+
+	resends := max(result.Attempts-1, 0)
+	span.SetAttributes(outcome...)
+	span.SetAttributes(resendKey.Int(resends))
+	span.End()
+
+	set := metric.WithAttributeSet(attribute.NewSet(attrs...))
+	h.duration.Record(ctx, result.Duration.Seconds(), set)
+	h.retries.Record(ctx, int64(resends), set)
+}
+
+// spanName returns the operation, or the method for unknown endpoints.
+func spanName(info transport.RequestInfo) string {
+	if info.Operation != "" {
+		return info.Operation
+	}
+	return info.Method
+}
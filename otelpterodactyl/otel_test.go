@@ -0,0 +1,138 @@
+package otelpterodactyl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/transport"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHooks(t *testing.T) {
+	var requests int32
+	var traceparents []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("Traceparent"))
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	hooks, err := Hooks(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithPropagators(propagation.TraceContext{}),
+	)
+	if err != nil {
+		t.Fatalf("Hooks() failed: %v", err)
+	}
+	tp := transport.New(http.DefaultTransport, "ptla_key", "v1", "test-agent",
+		transport.WithRetryWaitMin(time.Millisecond), transport.WithRetryWaitMax(time.Millisecond),
+		transport.WithRateLimiter(nil), transport.WithHooks(hooks))
+	client := &http.Client{Transport: tp}
+
+	resp, err := client.Get(server.URL + "/api/application/servers/7")
+	if err != nil {
+		t.Fatalf("client.Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(ended))
+	}
+	span := ended[0]
+	if span.Name() != "application.GetServer" || span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span = %q (%v), want client span application.GetServer", span.Name(), span.SpanKind())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want error", span.Status())
+	}
+	attrs := attribute.NewSet(span.Attributes()...)
+	for key, want := range map[attribute.Key]attribute.Value{
+		ServerIDKey:                 attribute.StringValue("7"),
+		"http.response.status_code": attribute.IntValue(http.StatusNotFound),
+		"http.request.resend_count": attribute.IntValue(1),
+		"pterodactyl.operation":     attribute.StringValue("application.GetServer"),
+		"http.request.method":       attribute.StringValue(http.MethodGet),
+		"url.path":                  attribute.StringValue("/api/application/servers/7"),
+		"error.type":                attribute.StringValue("404"),
+	} {
+		if got, _ := attrs.Value(key); got != want {
+			t.Errorf("span attribute %s = %v, want %v", key, got.Emit(), want.Emit())
+		}
+	}
+	if events := span.Events(); len(events) != 1 || events[0].Name != "retry" {
+		t.Errorf("span events = %v, want one retry", events)
+	}
+
+	traceID := span.SpanContext().TraceID().String()
+	for i, tp := range traceparents {
+		if tp == "" || tp[3:35] != traceID {
+			t.Errorf("attempt %d has traceparent %q, want trace %s", i+1, tp, traceID)
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	duration, ok := metrics["pterodactyl.client.request.duration"].(metricdata.Histogram[float64])
+	if !ok || len(duration.DataPoints) != 1 || duration.DataPoints[0].Count != 1 {
+		t.Errorf("duration histogram = %+v, want one call", metrics["pterodactyl.client.request.duration"])
+	} else if _, ok := duration.DataPoints[0].Attributes.Value(ServerIDKey); ok {
+		t.Error("duration histogram has a server ID attribute")
+	}
+	retries, ok := metrics["pterodactyl.client.request.retries"].(metricdata.Histogram[int64])
+	if !ok || len(retries.DataPoints) != 1 || retries.DataPoints[0].Sum != 1 {
+		t.Errorf("retries histogram = %+v, want one retry", metrics["pterodactyl.client.request.retries"])
+	}
+}
+
+func TestHooks_unknownEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	hooks, err := Hooks(WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))))
+	if err != nil {
+		t.Fatalf("Hooks() failed: %v", err)
+	}
+	tp := transport.New(http.DefaultTransport, "ptla_key", "v1", "test-agent",
+		transport.WithRateLimiter(nil), transport.WithHooks(hooks))
+	resp, err := (&http.Client{Transport: tp}).Get(server.URL + "/health")
+	if err != nil {
+		t.Fatalf("client.Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	ended := spans.Ended()
+	if len(ended) != 1 || ended[0].Name() != http.MethodGet || ended[0].Status().Code == codes.Error {
+		t.Errorf("spans = %v, want one successful GET span", ended)
+	}
+}
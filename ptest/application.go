@@ -0,0 +1,808 @@
+package ptest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/application"
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+// registerApplication registers the Application API handlers.
+func (p *Panel) registerApplication(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/application/users", p.listUsers)
+	mux.HandleFunc("POST /api/application/users", p.createUser)
+	mux.HandleFunc("GET /api/application/users/external/{external}", p.getUserExternal)
+	mux.HandleFunc("GET /api/application/users/{id}", p.getUser)
+	mux.HandleFunc("PATCH /api/application/users/{id}", p.updateUser)
+	mux.HandleFunc("DELETE /api/application/users/{id}", p.deleteUser)
+
+	mux.HandleFunc("GET /api/application/servers", p.listServers)
+	mux.HandleFunc("POST /api/application/servers", p.createServer)
+	mux.HandleFunc("GET /api/application/servers/external/{external}", p.getServerExternal)
+	mux.HandleFunc("GET /api/application/servers/{id}", p.getServer)
+	mux.HandleFunc("PATCH /api/application/servers/{id}/details", p.updateServerDetails)
+	mux.HandleFunc("PATCH /api/application/servers/{id}/build", p.updateServerBuild)
+	mux.HandleFunc("PATCH /api/application/servers/{id}/startup", p.updateServerStartup)
+	mux.HandleFunc("POST /api/application/servers/{id}/suspend", p.suspendServer(true))
+	mux.HandleFunc("POST /api/application/servers/{id}/unsuspend", p.suspendServer(false))
+	mux.HandleFunc("POST /api/application/servers/{id}/reinstall", p.reinstallServer)
+	mux.HandleFunc("DELETE /api/application/servers/{id}", p.deleteServer)
+	mux.HandleFunc("DELETE /api/application/servers/{id}/force", p.deleteServer)
+
+	mux.HandleFunc("GET /api/application/nodes", p.listNodes)
+	mux.HandleFunc("POST /api/application/nodes", p.createNode)
+	mux.HandleFunc("GET /api/application/nodes/deployable", p.deployableNodes)
+	mux.HandleFunc("GET /api/application/nodes/{id}", p.getNode)
+	mux.HandleFunc("PATCH /api/application/nodes/{id}", p.updateNode)
+	mux.HandleFunc("DELETE /api/application/nodes/{id}", p.deleteNode)
+	mux.HandleFunc("GET /api/application/nodes/{id}/allocations", p.listNodeAllocations)
+	mux.HandleFunc("POST /api/application/nodes/{id}/allocations", p.createNodeAllocations)
+	mux.HandleFunc("DELETE /api/application/nodes/{id}/allocations/{allocation}", p.deleteNodeAllocation)
+
+	mux.HandleFunc("GET /api/application/locations", p.listLocations)
+	mux.HandleFunc("POST /api/application/locations", p.createLocation)
+	mux.HandleFunc("GET /api/application/locations/{id}", p.getLocation)
+	mux.HandleFunc("PATCH /api/application/locations/{id}", p.updateLocation)
+	mux.HandleFunc("DELETE /api/application/locations/{id}", p.deleteLocation)
+
+	mux.HandleFunc("GET /api/application/nests", p.listNests)
+	mux.HandleFunc("GET /api/application/nests/{id}", p.getNest)
+	mux.HandleFunc("GET /api/application/nests/{id}/eggs", p.listEggs)
+	mux.HandleFunc("GET /api/application/nests/{id}/eggs/{egg}", p.getEgg)
+}
+
+// lookup finds a resource by the numeric path value "id". It writes a 404
+// response and returns nil if it does not exist. p.mu must be held.
+func lookup[T any](w http.ResponseWriter, r *http.Request, m map[int]*T) *T {
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeNotFound(w)
+		return nil
+	}
+	v, ok := m[id]
+	if !ok {
+		writeNotFound(w)
+		return nil
+	}
+	return v
+}
+
+// Users
+
+func (p *Panel) listUsers(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	writeJSON(w, http.StatusOK, paginated(r, "user", sortedValues(p.users)))
+}
+
+func (p *Panel) getUser(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if u := lookup(w, r, p.users); u != nil {
+		writeJSON(w, http.StatusOK, item("user", u))
+	}
+}
+
+func (p *Panel) getUserExternal(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	external := r.PathValue("external")
+	for _, u := range sortedValues(p.users) {
+		if u.ExternalID != nil && *u.ExternalID == external {
+			writeJSON(w, http.StatusOK, item("user", u))
+			return
+		}
+	}
+	writeNotFound(w)
+}
+
+// userConflictLocked returns the field of a user that collides with another
+// user, or "" if there is none. p.mu must be held.
+func (p *Panel) userConflictLocked(id int, email, username, externalID string) string {
+	for _, u := range p.users {
+		if u.ID == id {
+			continue
+		}
+		switch {
+		case email != "" && strings.EqualFold(u.Email, email):
+			return "email"
+		case username != "" && strings.EqualFold(u.Username, username):
+			return "username"
+		case externalID != "" && u.ExternalID != nil && *u.ExternalID == externalID:
+			return "external_id"
+		}
+	}
+	return ""
+}
+
+func (p *Panel) createUser(w http.ResponseWriter, r *http.Request) {
+	var req application.CreateUserRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if field := p.userConflictLocked(0, req.Email, req.Username, req.ExternalID); field != "" {
+		writeValidationError(w, field, fmt.Sprintf("The %s has already been taken.", field))
+		return
+	}
+
+	u := models.User{
+		ID:        p.newID(),
+		UUID:      newUUID(),
+		Username:  req.Username,
+		Email:     req.Email,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Language:  "en",
+		RootAdmin: req.RootAdmin,
+	}
+	if req.ExternalID != "" {
+		u.ExternalID = &req.ExternalID
+	}
+	stamp(&u.CreatedAt, &u.UpdatedAt)
+	p.users[u.ID] = &u
+	if p.account == 0 {
+		p.account = u.ID
+	}
+	writeJSON(w, http.StatusCreated, item("user", &u))
+}
+
+func (p *Panel) updateUser(w http.ResponseWriter, r *http.Request) {
+	var req application.UpdateUserRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	u := lookup(w, r, p.users)
+	if u == nil {
+		return
+	}
+	if field := p.userConflictLocked(u.ID, req.Email, req.Username, req.ExternalID); field != "" {
+		writeValidationError(w, field, fmt.Sprintf("The %s has already been taken.", field))
+		return
+	}
+
+	setString(&u.Email, req.Email)
+	setString(&u.Username, req.Username)
+	setString(&u.FirstName, req.FirstName)
+	setString(&u.LastName, req.LastName)
+	if req.RootAdmin != nil {
+		u.RootAdmin = *req.RootAdmin
+	}
+	if req.ExternalID != "" {
+		u.ExternalID = &req.ExternalID
+	}
+	u.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, item("user", u))
+}
+
+func (p *Panel) deleteUser(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	u := lookup(w, r, p.users)
+	if u == nil {
+		return
+	}
+	for _, s := range p.servers {
+		if s.UserID == u.ID {
+			writeError(w, http.StatusBadRequest, "DisplayException", "Cannot delete a user with active servers attached to their account.")
+			return
+		}
+	}
+	delete(p.users, u.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Servers
+
+func (p *Panel) listServers(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	writeJSON(w, http.StatusOK, paginated(r, "server", sortedValues(p.servers)))
+}
+
+func (p *Panel) getServer(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s := lookup(w, r, p.servers); s != nil {
+		writeJSON(w, http.StatusOK, item("server", s))
+	}
+}
+
+func (p *Panel) getServerExternal(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	external := r.PathValue("external")
+	for _, s := range sortedValues(p.servers) {
+		if s.ExternalID != nil && *s.ExternalID == external {
+			writeJSON(w, http.StatusOK, item("server", s))
+			return
+		}
+	}
+	writeNotFound(w)
+}
+
+func (p *Panel) createServer(w http.ResponseWriter, r *http.Request) {
+	var req application.CreateServerRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if req.Name == "" {
+		writeValidationError(w, "name", "The name field is required.")
+		return
+	}
+	if _, ok := p.users[req.User]; !ok {
+		writeValidationError(w, "user", "The selected user is invalid.")
+		return
+	}
+	egg, ok := p.eggs[req.Egg]
+	if !ok {
+		writeValidationError(w, "egg", "The selected egg is invalid.")
+		return
+	}
+	if req.ExternalID != "" {
+		for _, s := range p.servers {
+			if s.ExternalID != nil && *s.ExternalID == req.ExternalID {
+				writeValidationError(w, "external_id", "The external_id has already been taken.")
+				return
+			}
+		}
+	}
+	alloc, ok := p.allocations[req.Allocation.Default]
+	if !ok || alloc.ServerID != 0 {
+		writeValidationError(w, "allocation.default", "The selected allocation.default is invalid.")
+		return
+	}
+
+	s := models.Server{
+		Name:          req.Name,
+		Description:   req.Description,
+		UserID:        req.User,
+		NodeID:        p.allocationNodes[alloc.ID],
+		AllocationID:  alloc.ID,
+		NestID:        egg.Nest,
+		EggID:         egg.ID,
+		DockerImage:   req.DockerImage,
+		Invocation:    req.Startup,
+		Limits:        req.Limits,
+		FeatureLimits: req.FeatureLimits,
+	}
+	if s.DockerImage == "" {
+		s.DockerImage = egg.DockerImage
+	}
+	if s.Invocation == "" {
+		s.Invocation = egg.Startup
+	}
+	if req.ExternalID != "" {
+		s.ExternalID = &req.ExternalID
+	}
+	created := p.addServerLocked(s)
+	for _, id := range req.Allocation.Additional {
+		if a, ok := p.allocations[id]; ok && a.ServerID == 0 {
+			a.ServerID = created.ID
+		}
+	}
+	writeJSON(w, http.StatusCreated, item("server", p.servers[created.ID]))
+}
+
+func (p *Panel) updateServerDetails(w http.ResponseWriter, r *http.Request) {
+	var req application.UpdateServerDetailsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := lookup(w, r, p.servers)
+	if s == nil {
+		return
+	}
+	if req.User != 0 {
+		if _, ok := p.users[req.User]; !ok {
+			writeValidationError(w, "user", "The selected user is invalid.")
+			return
+		}
+		s.UserID = req.User
+	}
+	setString(&s.Name, req.Name)
+	setString(&s.Description, req.Description)
+	if req.ExternalID != "" {
+		s.ExternalID = &req.ExternalID
+	}
+	s.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, item("server", s))
+}
+
+func (p *Panel) updateServerBuild(w http.ResponseWriter, r *http.Request) {
+	var req application.UpdateServerBuildRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := lookup(w, r, p.servers)
+	if s == nil {
+		return
+	}
+	for _, id := range req.AddAllocations {
+		if a, ok := p.allocations[id]; ok && a.ServerID == 0 {
+			a.ServerID = s.ID
+		}
+	}
+	for _, id := range req.RemoveAllocations {
+		if a, ok := p.allocations[id]; ok && a.ServerID == s.ID && id != req.AllocationID {
+			a.ServerID = 0
+		}
+	}
+	if a, ok := p.allocations[req.AllocationID]; ok && a.ServerID == s.ID {
+		s.AllocationID = a.ID
+	}
+	s.Limits.Memory = req.Memory
+	s.Limits.Swap = req.Swap
+	s.Limits.Disk = req.Disk
+	s.Limits.IO = req.IO
+	s.Limits.CPU = req.CPU
+	if req.Threads != "" {
+		s.Limits.Threads = &req.Threads
+	}
+	s.FeatureLimits = req.FeatureLimits
+	s.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, item("server", s))
+}
+
+func (p *Panel) updateServerStartup(w http.ResponseWriter, r *http.Request) {
+	var req application.UpdateServerStartupRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := lookup(w, r, p.servers)
+	if s == nil {
+		return
+	}
+	egg, ok := p.eggs[req.Egg]
+	if !ok {
+		writeValidationError(w, "egg", "The selected egg is invalid.")
+		return
+	}
+	s.EggID = egg.ID
+	s.NestID = egg.Nest
+	s.Invocation = req.Startup
+	s.DockerImage = req.Image
+	s.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, item("server", s))
+}
+
+func (p *Panel) suspendServer(suspended bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		s := lookup(w, r, p.servers)
+		if s == nil {
+			return
+		}
+		s.Suspended = suspended
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (p *Panel) reinstallServer(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s := lookup(w, r, p.servers); s != nil {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (p *Panel) deleteServer(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	s := lookup(w, r, p.servers)
+	if s == nil {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.servers, s.ID)
+	delete(p.runtime, s.Identifier)
+	for _, a := range p.allocations {
+		if a.ServerID == s.ID {
+			a.ServerID = 0
+			a.IsDefault = false
+		}
+	}
+	p.mu.Unlock()
+
+	p.wings.disconnect(s.Identifier)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Nodes
+
+func (p *Panel) listNodes(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	writeJSON(w, http.StatusOK, paginated(r, "node", sortedValues(p.nodes)))
+}
+
+func (p *Panel) getNode(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n := lookup(w, r, p.nodes); n != nil {
+		writeJSON(w, http.StatusOK, item("node", n))
+	}
+}
+
+func (p *Panel) createNode(w http.ResponseWriter, r *http.Request) {
+	var req application.CreateNodeRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.locations[req.LocationID]; !ok {
+		writeValidationError(w, "location_id", "The selected location id is invalid.")
+		return
+	}
+	n := models.Node{
+		ID:                 p.newID(),
+		UUID:               newUUID(),
+		Public:             req.Public,
+		Name:               req.Name,
+		Description:        req.Description,
+		LocationID:         req.LocationID,
+		FQDN:               req.FQDN,
+		Scheme:             req.Scheme,
+		BehindProxy:        req.BehindProxy,
+		MaintenanceMode:    req.MaintenanceMode,
+		Memory:             req.Memory,
+		MemoryOverallocate: req.MemoryOverallocate,
+		Disk:               req.Disk,
+		DiskOverallocate:   req.DiskOverallocate,
+		UploadSize:         req.UploadSize,
+		DaemonListen:       req.DaemonListen,
+		DaemonSFTP:         req.DaemonSFTP,
+		DaemonBase:         req.DaemonBase,
+	}
+	if n.Scheme == "" {
+		n.Scheme = "https"
+	}
+	stamp(&n.CreatedAt, &n.UpdatedAt)
+	p.nodes[n.ID] = &n
+	writeJSON(w, http.StatusCreated, item("node", &n))
+}
+
+func (p *Panel) updateNode(w http.ResponseWriter, r *http.Request) {
+	var req application.UpdateNodeRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := lookup(w, r, p.nodes)
+	if n == nil {
+		return
+	}
+	if req.LocationID != 0 {
+		if _, ok := p.locations[req.LocationID]; !ok {
+			writeValidationError(w, "location_id", "The selected location id is invalid.")
+			return
+		}
+		n.LocationID = req.LocationID
+	}
+	setString(&n.Name, req.Name)
+	setString(&n.FQDN, req.FQDN)
+	setString(&n.Scheme, req.Scheme)
+	setString(&n.DaemonBase, req.DaemonBase)
+	setString(&n.Description, req.Description)
+	if req.Memory != 0 {
+		n.Memory = req.Memory
+	}
+	if req.Disk != 0 {
+		n.Disk = req.Disk
+	}
+	if req.Public != nil {
+		n.Public = *req.Public
+	}
+	if req.BehindProxy != nil {
+		n.BehindProxy = *req.BehindProxy
+	}
+	if req.MaintenanceMode != nil {
+		n.MaintenanceMode = *req.MaintenanceMode
+	}
+	n.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, item("node", n))
+}
+
+func (p *Panel) deleteNode(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := lookup(w, r, p.nodes)
+	if n == nil {
+		return
+	}
+	for _, s := range p.servers {
+		if s.NodeID == n.ID {
+			writeError(w, http.StatusBadRequest, "DisplayException", "Cannot delete a node with active servers attached to it.")
+			return
+		}
+	}
+	delete(p.nodes, n.ID)
+	for id, nodeID := range p.allocationNodes {
+		if nodeID == n.ID {
+			delete(p.allocations, id)
+			delete(p.allocationNodes, id)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deployableNodes returns public nodes with enough unallocated memory and disk.
+func (p *Panel) deployableNodes(w http.ResponseWriter, r *http.Request) {
+	memory, _ := strconv.ParseInt(r.URL.Query().Get("memory"), 10, 64)
+	disk, _ := strconv.ParseInt(r.URL.Query().Get("disk"), 10, 64)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var nodes []*models.Node
+	for _, n := range sortedValues(p.nodes) {
+		if !n.Public || n.MaintenanceMode {
+			continue
+		}
+		var usedMemory, usedDisk int64
+		for _, s := range p.servers {
+			if s.NodeID == n.ID {
+				usedMemory += s.Limits.Memory
+				usedDisk += s.Limits.Disk
+			}
+		}
+		if n.Memory-usedMemory >= memory && n.Disk-usedDisk >= disk {
+			nodes = append(nodes, n)
+		}
+	}
+	writeJSON(w, http.StatusOK, list("node", nodes))
+}
+
+func (p *Panel) listNodeAllocations(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := lookup(w, r, p.nodes)
+	if n == nil {
+		return
+	}
+	var allocations []*models.Allocation
+	for _, a := range sortedValues(p.allocations) {
+		if p.allocationNodes[a.ID] == n.ID {
+			allocations = append(allocations, a)
+		}
+	}
+	writeJSON(w, http.StatusOK, paginated(r, "allocation", allocations))
+}
+
+func (p *Panel) createNodeAllocations(w http.ResponseWriter, r *http.Request) {
+	var req application.CreateNodeAllocationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	var ports []int
+	for _, spec := range req.Ports {
+		expanded, err := expandPorts(spec)
+		if err != nil {
+			writeValidationError(w, "ports", err.Error())
+			return
+		}
+		ports = append(ports, expanded...)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := lookup(w, r, p.nodes)
+	if n == nil {
+		return
+	}
+	for _, port := range ports {
+		exists := false
+		for id, a := range p.allocations {
+			if p.allocationNodes[id] == n.ID && a.IP == req.IP && a.Port == port {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+		a := &models.Allocation{ID: p.newID(), IP: req.IP, Port: port}
+		if req.Alias != "" {
+			alias := req.Alias
+			a.Alias = &alias
+		}
+		p.allocations[a.ID] = a
+		p.allocationNodes[a.ID] = n.ID
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// expandPorts parses a port or an inclusive port range such as "25565-25570".
+func expandPorts(spec string) ([]int, error) {
+	lo, hi, isRange := strings.Cut(spec, "-")
+	start, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", spec)
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil || end < start {
+			return nil, fmt.Errorf("invalid port range %q", spec)
+		}
+	}
+	if start < 1024 || end > 65535 {
+		return nil, fmt.Errorf("port %q must be between 1024 and 65535", spec)
+	}
+	ports := make([]int, 0, end-start+1)
+	for port := start; port <= end; port++ {
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+func (p *Panel) deleteNodeAllocation(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := lookup(w, r, p.nodes)
+	if n == nil {
+		return
+	}
+	id, _ := pathID(r, "allocation")
+	a, ok := p.allocations[id]
+	if !ok || p.allocationNodes[id] != n.ID {
+		writeNotFound(w)
+		return
+	}
+	if a.ServerID != 0 {
+		writeError(w, http.StatusBadRequest, "DisplayException", "Cannot delete an allocation that is assigned to a server.")
+		return
+	}
+	delete(p.allocations, id)
+	delete(p.allocationNodes, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Locations
+
+func (p *Panel) listLocations(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	writeJSON(w, http.StatusOK, paginated(r, "location", sortedValues(p.locations)))
+}
+
+func (p *Panel) getLocation(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if l := lookup(w, r, p.locations); l != nil {
+		writeJSON(w, http.StatusOK, item("location", l))
+	}
+}
+
+func (p *Panel) createLocation(w http.ResponseWriter, r *http.Request) {
+	var req application.CreateLocationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if req.Short == "" {
+		writeValidationError(w, "short", "The short field is required.")
+		return
+	}
+	l := models.Location{ID: p.newID(), Short: req.Short, Long: req.Long}
+	stamp(&l.CreatedAt, &l.UpdatedAt)
+	p.locations[l.ID] = &l
+	writeJSON(w, http.StatusCreated, item("location", &l))
+}
+
+func (p *Panel) updateLocation(w http.ResponseWriter, r *http.Request) {
+	var req application.UpdateLocationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	l := lookup(w, r, p.locations)
+	if l == nil {
+		return
+	}
+	setString(&l.Short, req.Short)
+	setString(&l.Long, req.Long)
+	l.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, item("location", l))
+}
+
+func (p *Panel) deleteLocation(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	l := lookup(w, r, p.locations)
+	if l == nil {
+		return
+	}
+	for _, n := range p.nodes {
+		if n.LocationID == l.ID {
+			writeError(w, http.StatusBadRequest, "DisplayException", "Cannot delete a location that has active nodes attached to it.")
+			return
+		}
+	}
+	delete(p.locations, l.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Nests and eggs
+
+func (p *Panel) listNests(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	writeJSON(w, http.StatusOK, paginated(r, "nest", sortedValues(p.nests)))
+}
+
+func (p *Panel) getNest(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n := lookup(w, r, p.nests); n != nil {
+		writeJSON(w, http.StatusOK, item("nest", n))
+	}
+}
+
+func (p *Panel) listEggs(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := lookup(w, r, p.nests)
+	if n == nil {
+		return
+	}
+	var eggs []*models.Egg
+	for _, e := range sortedValues(p.eggs) {
+		if e.Nest == n.ID {
+			eggs = append(eggs, e)
+		}
+	}
+	writeJSON(w, http.StatusOK, paginated(r, "egg", eggs))
+}
+
+func (p *Panel) getEgg(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := lookup(w, r, p.nests)
+	if n == nil {
+		return
+	}
+	id, _ := pathID(r, "egg")
+	e, ok := p.eggs[id]
+	if !ok || e.Nest != n.ID {
+		writeNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, item("egg", e))
+}
+
+// setString sets dst to v unless v is empty.
+func setString(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
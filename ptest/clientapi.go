@@ -0,0 +1,1076 @@
+package ptest
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/client"
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+// maxAPIKeys is the number of API keys an account may have.
+const maxAPIKeys = 25
+
+// registerClient registers the Client API handlers and the signed download route.
+func (p *Panel) registerClient(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/client", p.listClientServers)
+	mux.HandleFunc("GET /api/client/account", p.getAccount)
+	mux.HandleFunc("GET /api/client/account/api-keys", p.listAPIKeys)
+	mux.HandleFunc("POST /api/client/account/api-keys", p.createAPIKey)
+	mux.HandleFunc("DELETE /api/client/account/api-keys/{identifier}", p.deleteAPIKey)
+
+	mux.HandleFunc("GET /api/client/servers/{server}", p.serverRoute(true, p.getClientServer))
+	mux.HandleFunc("GET /api/client/servers/{server}/resources", p.serverRoute(true, p.getResources))
+	mux.HandleFunc("GET /api/client/servers/{server}/websocket", p.serverRoute(true, p.getWebSocket))
+	mux.HandleFunc("POST /api/client/servers/{server}/power", p.sendPower)
+	mux.HandleFunc("POST /api/client/servers/{server}/command", p.serverRoute(false, p.sendCommand))
+
+	mux.HandleFunc("GET /api/client/servers/{server}/files/list", p.serverRoute(false, p.listFiles))
+	mux.HandleFunc("GET /api/client/servers/{server}/files/contents", p.serverRoute(false, p.getFileContents))
+	mux.HandleFunc("POST /api/client/servers/{server}/files/write", p.serverRoute(false, p.writeFile))
+	mux.HandleFunc("POST /api/client/servers/{server}/files/create-folder", p.serverRoute(false, p.createFolder))
+	mux.HandleFunc("POST /api/client/servers/{server}/files/delete", p.serverRoute(false, p.deleteFiles))
+	mux.HandleFunc("PUT /api/client/servers/{server}/files/rename", p.serverRoute(false, p.renameFiles))
+	mux.HandleFunc("POST /api/client/servers/{server}/files/copy", p.serverRoute(false, p.copyFile))
+	mux.HandleFunc("GET /api/client/servers/{server}/files/download", p.serverRoute(false, p.downloadFile))
+
+	mux.HandleFunc("GET /api/client/servers/{server}/backups", p.serverRoute(false, p.listBackups))
+	mux.HandleFunc("POST /api/client/servers/{server}/backups", p.serverRoute(false, p.createBackup))
+	mux.HandleFunc("GET /api/client/servers/{server}/backups/{backup}", p.serverRoute(false, p.getBackup))
+	mux.HandleFunc("DELETE /api/client/servers/{server}/backups/{backup}", p.serverRoute(false, p.deleteBackup))
+	mux.HandleFunc("POST /api/client/servers/{server}/backups/{backup}/lock", p.serverRoute(false, p.lockBackup))
+	mux.HandleFunc("POST /api/client/servers/{server}/backups/{backup}/restore", p.serverRoute(false, p.restoreBackup))
+	mux.HandleFunc("GET /api/client/servers/{server}/backups/{backup}/download", p.serverRoute(false, p.downloadBackup))
+
+	mux.HandleFunc("GET /api/client/servers/{server}/schedules", p.serverRoute(false, p.listSchedules))
+	mux.HandleFunc("POST /api/client/servers/{server}/schedules", p.serverRoute(false, p.createSchedule))
+	mux.HandleFunc("GET /api/client/servers/{server}/schedules/{schedule}", p.serverRoute(false, p.getSchedule))
+	mux.HandleFunc("POST /api/client/servers/{server}/schedules/{schedule}", p.serverRoute(false, p.updateSchedule))
+	mux.HandleFunc("DELETE /api/client/servers/{server}/schedules/{schedule}", p.serverRoute(false, p.deleteSchedule))
+	mux.HandleFunc("POST /api/client/servers/{server}/schedules/{schedule}/execute", p.executeSchedule)
+	mux.HandleFunc("POST /api/client/servers/{server}/schedules/{schedule}/tasks", p.serverRoute(false, p.createTask))
+	mux.HandleFunc("POST /api/client/servers/{server}/schedules/{schedule}/tasks/{task}", p.serverRoute(false, p.updateTask))
+	mux.HandleFunc("DELETE /api/client/servers/{server}/schedules/{schedule}/tasks/{task}", p.serverRoute(false, p.deleteTask))
+
+	mux.HandleFunc("GET /api/client/servers/{server}/databases", p.serverRoute(false, p.listDatabases))
+	mux.HandleFunc("POST /api/client/servers/{server}/databases", p.serverRoute(false, p.createDatabase))
+	mux.HandleFunc("POST /api/client/servers/{server}/databases/{database}/rotate-password", p.serverRoute(false, p.rotateDatabasePassword))
+	mux.HandleFunc("DELETE /api/client/servers/{server}/databases/{database}", p.serverRoute(false, p.deleteDatabase))
+
+	mux.HandleFunc("GET /api/client/servers/{server}/network/allocations", p.serverRoute(false, p.listServerAllocations))
+	mux.HandleFunc("POST /api/client/servers/{server}/network/allocations", p.serverRoute(false, p.assignAllocation))
+	mux.HandleFunc("POST /api/client/servers/{server}/network/allocations/{allocation}", p.serverRoute(false, p.updateAllocationNotes))
+	mux.HandleFunc("POST /api/client/servers/{server}/network/allocations/{allocation}/primary", p.serverRoute(false, p.setPrimaryAllocation))
+	mux.HandleFunc("DELETE /api/client/servers/{server}/network/allocations/{allocation}", p.serverRoute(false, p.deleteServerAllocation))
+
+	mux.HandleFunc("POST /api/client/servers/{server}/settings/rename", p.serverRoute(false, p.renameServer))
+	mux.HandleFunc("POST /api/client/servers/{server}/settings/reinstall", p.serverRoute(false, p.reinstallClientServer))
+	mux.HandleFunc("PUT /api/client/servers/{server}/settings/docker-image", p.serverRoute(false, p.setDockerImage))
+
+	mux.HandleFunc("GET /download", p.serveDownload)
+}
+
+// serverHandler handles a request for a single server. p.mu is held.
+type serverHandler func(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState)
+
+// serverRoute resolves the {server} path value and calls fn with p.mu held.
+// Unless allowConflict is set, requests for suspended or installing servers are
+// rejected with 409 Conflict like the real panel does.
+func (p *Panel) serverRoute(allowConflict bool, fn serverHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if s, rt, ok := p.resolveServerLocked(w, r, allowConflict); ok {
+			fn(w, r, s, rt)
+		}
+	}
+}
+
+// resolveServerLocked finds the server of a request, writing an error response
+// if it does not exist or is in a conflicting state. p.mu must be held.
+func (p *Panel) resolveServerLocked(w http.ResponseWriter, r *http.Request, allowConflict bool) (*models.Server, *serverState, bool) {
+	s := p.serverLocked(r.PathValue("server"))
+	if s == nil {
+		writeNotFound(w)
+		return nil, nil, false
+	}
+	if !allowConflict {
+		switch {
+		case s.Suspended:
+			writeError(w, http.StatusConflict, "ServerStateConflictException", "This server is currently suspended and the functionality requested is unavailable.")
+			return nil, nil, false
+		case s.Installing:
+			writeError(w, http.StatusConflict, "ServerStateConflictException", "This server has not yet completed its installation process, please try again later.")
+			return nil, nil, false
+		}
+	}
+	return s, p.runtime[s.Identifier], true
+}
+
+// Account
+
+func (p *Panel) listClientServers(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	writeJSON(w, http.StatusOK, paginated(r, "server", sortedValues(p.servers)))
+}
+
+func (p *Panel) getAccount(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	u, ok := p.users[p.account]
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, item("user", u))
+}
+
+func (p *Panel) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	keys := make([]models.APIKey, 0, len(p.apiKeys))
+	for _, k := range p.apiKeys {
+		keys = append(keys, k.key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	writeJSON(w, http.StatusOK, list("api_key", keys))
+}
+
+func (p *Panel) createAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Description string   `json:"description"`
+		AllowedIPs  []string `json:"allowed_ips"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if req.Description == "" {
+		writeValidationError(w, "description", "The description field is required.")
+		return
+	}
+	if len(p.apiKeys) >= maxAPIKeys {
+		writeError(w, http.StatusBadRequest, "DisplayException", "You have reached the account limit for number of API keys.")
+		return
+	}
+	k := p.registerKeyLocked("ptlc_"+randomHex(22)[:43], req.Description)
+	if req.AllowedIPs != nil {
+		k.key.AllowedIPs = req.AllowedIPs
+	}
+	resp := item("api_key", k.key)
+	resp["meta"] = map[string]string{"secret_token": k.token}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (p *Panel) deleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	identifier := r.PathValue("identifier")
+	if _, ok := p.apiKeys[identifier]; !ok {
+		writeNotFound(w)
+		return
+	}
+	delete(p.apiKeys, identifier)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Servers
+
+func (p *Panel) getClientServer(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	writeJSON(w, http.StatusOK, item("server", s))
+}
+
+func (p *Panel) getResources(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	writeJSON(w, http.StatusOK, item("stats", models.Stats{
+		CurrentState: rt.state,
+		IsSuspended:  s.Suspended,
+		Resources:    rt.resources,
+	}))
+}
+
+func (p *Panel) getWebSocket(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	token := p.wings.issue(s.Identifier)
+	socket := "ws" + strings.TrimPrefix(p.server.URL, "http") + "/wings/servers/" + s.Identifier + "/ws"
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]string{"token": token, "socket": socket},
+	})
+}
+
+func (p *Panel) sendPower(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Signal string `json:"signal"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p.mu.Lock()
+	s, _, ok := p.resolveServerLocked(w, r, false)
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+	if !validSignal(req.Signal) {
+		writeValidationError(w, "signal", "The selected signal is invalid.")
+		return
+	}
+	p.power(s.Identifier, req.Signal)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validSignal reports whether signal is a known power signal.
+func validSignal(signal string) bool {
+	for _, v := range client.ValidPowerSignals {
+		if v == signal {
+			return true
+		}
+	}
+	return false
+}
+
+// power moves a server through the states of a power signal, notifying
+// WebSocket listeners of each one.
+func (p *Panel) power(identifier, signal string) {
+	p.mu.Lock()
+	rt, ok := p.runtime[identifier]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	current, handler := rt.state, p.powerHandler
+	p.mu.Unlock()
+
+	var states []string
+	if handler != nil {
+		states = handler(identifier, signal)
+	} else {
+		states = defaultPowerStates(current, signal)
+	}
+	for _, state := range states {
+		p.SetState(identifier, state)
+	}
+}
+
+// defaultPowerStates returns the states a server goes through for a signal.
+func defaultPowerStates(current, signal string) []string {
+	switch signal {
+	case "start":
+		if current == "offline" {
+			return []string{"starting", "running"}
+		}
+	case "stop":
+		if current != "offline" {
+			return []string{"stopping", "offline"}
+		}
+	case "restart":
+		if current == "offline" {
+			return []string{"starting", "running"}
+		}
+		return []string{"stopping", "offline", "starting", "running"}
+	case "kill":
+		if current != "offline" {
+			return []string{"offline"}
+		}
+	}
+	return nil
+}
+
+func (p *Panel) sendCommand(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req struct {
+		Command string `json:"command"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if rt.state == "offline" {
+		writeError(w, http.StatusBadGateway, "HttpException", "Server must be online in order to send commands.")
+		return
+	}
+	rt.commands = append(rt.commands, req.Command)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Files
+
+// childOf returns the name of the entry directly below dir that leads to
+// target, and whether target is that entry itself.
+func childOf(dir, target string) (name string, direct, ok bool) {
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	rest, ok := strings.CutPrefix(target, prefix)
+	if !ok || rest == "" {
+		return "", false, false
+	}
+	name, _, nested := strings.Cut(rest, "/")
+	return name, !nested, true
+}
+
+func (p *Panel) listFiles(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	dir := cleanPath(r.URL.Query().Get("directory"))
+	now := time.Now().UTC().Truncate(time.Second)
+
+	entries := make(map[string]models.FileObject)
+	addDir := func(name string) {
+		entries[name] = models.FileObject{
+			Name: name, Mode: "drwxr-xr-x", ModeBits: "755", Size: 4096,
+			Mimetype: "inode/directory", CreatedAt: now, ModifiedAt: now,
+		}
+	}
+	for d := range rt.dirs {
+		if name, _, ok := childOf(dir, d); ok {
+			addDir(name)
+		}
+	}
+	for f, content := range rt.files {
+		name, direct, ok := childOf(dir, f)
+		switch {
+		case !ok:
+		case !direct:
+			addDir(name)
+		default:
+			entries[name] = models.FileObject{
+				Name: name, Mode: "-rw-r--r--", ModeBits: "644", Size: int64(len(content)),
+				IsFile: true, Mimetype: mimeType(name), CreatedAt: now, ModifiedAt: now,
+			}
+		}
+	}
+
+	files := make([]models.FileObject, 0, len(entries))
+	for _, e := range entries {
+		files = append(files, e)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].IsFile != files[j].IsFile {
+			return !files[i].IsFile
+		}
+		return files[i].Name < files[j].Name
+	})
+	writeJSON(w, http.StatusOK, list("file_object", files))
+}
+
+// mimeType guesses the media type of a file from its extension.
+func mimeType(name string) string {
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+	return "text/plain"
+}
+
+// getFileContents returns the file as a JSON string, which is the form
+// client.GetFileContents decodes.
+func (p *Panel) getFileContents(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	content, ok := rt.files[cleanPath(r.URL.Query().Get("file"))]
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, content)
+}
+
+// writeFile accepts a JSON-encoded string, as sent by client.WriteFile, or a
+// raw body.
+func (p *Panel) writeFile(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequestHttpException", err.Error())
+		return
+	}
+	content := string(body)
+	var decoded string
+	if json.Unmarshal(body, &decoded) == nil {
+		content = decoded
+	}
+	rt.files[cleanPath(r.URL.Query().Get("file"))] = content
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *Panel) createFolder(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req struct {
+		Root string `json:"root"`
+		Name string `json:"name"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	rt.dirs[cleanPath(req.Root+"/"+req.Name)] = true
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *Panel) deleteFiles(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req struct {
+		Root  string   `json:"root"`
+		Files []string `json:"files"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	for _, name := range req.Files {
+		target := cleanPath(req.Root + "/" + name)
+		for f := range rt.files {
+			if f == target || strings.HasPrefix(f, target+"/") {
+				delete(rt.files, f)
+			}
+		}
+		for d := range rt.dirs {
+			if d == target || strings.HasPrefix(d, target+"/") {
+				delete(rt.dirs, d)
+			}
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *Panel) renameFiles(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req struct {
+		Root  string `json:"root"`
+		Files []struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"files"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	for _, f := range req.Files {
+		from := cleanPath(req.Root + "/" + f.From)
+		to := cleanPath(req.Root + "/" + f.To)
+		for name, content := range rt.files {
+			if rest, ok := strings.CutPrefix(name, from); ok && (rest == "" || rest[0] == '/') {
+				delete(rt.files, name)
+				rt.files[to+rest] = content
+			}
+		}
+		for name := range rt.dirs {
+			if rest, ok := strings.CutPrefix(name, from); ok && (rest == "" || rest[0] == '/') {
+				delete(rt.dirs, name)
+				rt.dirs[to+rest] = true
+			}
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// copyFile copies a file next to itself as "name copy.ext", "name copy 2.ext" and so on.
+func (p *Panel) copyFile(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req struct {
+		Location string `json:"location"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	source := cleanPath(req.Location)
+	content, ok := rt.files[source]
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+	ext := path.Ext(source)
+	base := strings.TrimSuffix(source, ext)
+	dest := base + " copy" + ext
+	for i := 2; ; i++ {
+		if _, exists := rt.files[dest]; !exists {
+			break
+		}
+		dest = fmt.Sprintf("%s copy %d%s", base, i, ext)
+	}
+	rt.files[dest] = content
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *Panel) downloadFile(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	content, ok := rt.files[cleanPath(r.URL.Query().Get("file"))]
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, item("signed_url", models.SignedURL{URL: p.signLocked(content)}))
+}
+
+// signLocked stores content under a random token and returns its download URL.
+// p.mu must be held.
+func (p *Panel) signLocked(content string) string {
+	token := randomHex(16)
+	p.downloads[token] = content
+	return p.server.URL + "/download?token=" + token
+}
+
+// serveDownload serves content previously signed by signLocked.
+func (p *Panel) serveDownload(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	content, ok := p.downloads[r.URL.Query().Get("token")]
+	p.mu.Unlock()
+	if !ok {
+		http.Error(w, "invalid download token", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write([]byte(content))
+}
+
+// Backups
+
+func (p *Panel) listBackups(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	writeJSON(w, http.StatusOK, paginated(r, "backup", rt.backups))
+}
+
+// findBackup returns the backup named by the {backup} path value, writing a
+// 404 response if it does not exist.
+func findBackup(w http.ResponseWriter, r *http.Request, rt *serverState) (int, *models.Backup) {
+	for i, b := range rt.backups {
+		if b.UUID == r.PathValue("backup") {
+			return i, b
+		}
+	}
+	writeNotFound(w)
+	return -1, nil
+}
+
+func (p *Panel) getBackup(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	if _, b := findBackup(w, r, rt); b != nil {
+		writeJSON(w, http.StatusOK, item("backup", b))
+	}
+}
+
+func (p *Panel) createBackup(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req client.CreateBackupRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	b, err := createBackupLocked(s, rt, req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "TooManyBackupsException", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, item("backup", b))
+}
+
+// createBackupLocked creates a completed backup of the server's files. Like the
+// panel, a backup limit of zero disables backups. p.mu must be held.
+func createBackupLocked(s *models.Server, rt *serverState, req client.CreateBackupRequest) (*models.Backup, error) {
+	limit := s.FeatureLimits.Backups
+	if limit == 0 {
+		return nil, fmt.Errorf("Backups are disabled for this server")
+	}
+	if len(rt.backups) >= limit {
+		return nil, fmt.Errorf("Only %d backups may be created for this server.", limit)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	snapshot := make(map[string]string, len(rt.files))
+	var size int64
+	for name, content := range rt.files {
+		snapshot[name] = content
+		size += int64(len(content))
+	}
+	b := &models.Backup{
+		UUID:         newUUID(),
+		Name:         req.Name,
+		IgnoredFiles: []string{},
+		Bytes:        size,
+		CreatedAt:    now,
+		CompletedAt:  &now,
+		IsSuccessful: true,
+		IsLocked:     req.IsLocked,
+	}
+	if b.Name == "" {
+		b.Name = "Backup at " + now.Format("2006-01-02 15:04:05")
+	}
+	if req.Ignored != "" {
+		b.IgnoredFiles = strings.Fields(req.Ignored)
+	}
+	rt.backups = append(rt.backups, b)
+	rt.snapshots[b.UUID] = snapshot
+	return b, nil
+}
+
+func (p *Panel) deleteBackup(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	i, b := findBackup(w, r, rt)
+	if b == nil {
+		return
+	}
+	if b.IsLocked {
+		writeError(w, http.StatusBadRequest, "BackupLockedException", "Cannot delete a backup that is marked as locked.")
+		return
+	}
+	rt.backups = append(rt.backups[:i], rt.backups[i+1:]...)
+	delete(rt.snapshots, b.UUID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *Panel) lockBackup(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	if _, b := findBackup(w, r, rt); b != nil {
+		b.IsLocked = !b.IsLocked
+		writeJSON(w, http.StatusOK, item("backup", b))
+	}
+}
+
+// restoreBackup replaces the server's files with the backup's. Without
+// truncate, files created after the backup are kept.
+func (p *Panel) restoreBackup(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req struct {
+		Truncate bool `json:"truncate"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	_, b := findBackup(w, r, rt)
+	if b == nil {
+		return
+	}
+	if req.Truncate {
+		rt.files = make(map[string]string)
+	}
+	for name, content := range rt.snapshots[b.UUID] {
+		rt.files[name] = content
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// downloadBackup signs a gzipped tar archive of the backup's files.
+func (p *Panel) downloadBackup(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	_, b := findBackup(w, r, rt)
+	if b == nil {
+		return
+	}
+
+	snapshot := rt.snapshots[b.UUID]
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		content := snapshot[name]
+		tw.WriteHeader(&tar.Header{Name: strings.TrimPrefix(name, "/"), Mode: 0o644, Size: int64(len(content)), ModTime: b.CreatedAt})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+
+	writeJSON(w, http.StatusOK, item("signed_url", models.SignedURL{URL: p.signLocked(buf.String())}))
+}
+
+// Schedules
+
+// findSchedule returns the schedule named by the {schedule} path value,
+// writing a 404 response if it does not exist.
+func findSchedule(w http.ResponseWriter, r *http.Request, rt *serverState) (int, *models.Schedule) {
+	id, _ := pathID(r, "schedule")
+	for i, sc := range rt.schedules {
+		if sc.ID == id {
+			return i, sc
+		}
+	}
+	writeNotFound(w)
+	return -1, nil
+}
+
+func (p *Panel) listSchedules(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	writeJSON(w, http.StatusOK, list("server_schedule", rt.schedules))
+}
+
+func (p *Panel) getSchedule(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	if _, sc := findSchedule(w, r, rt); sc != nil {
+		writeJSON(w, http.StatusOK, item("server_schedule", sc))
+	}
+}
+
+// createSchedule creates a schedule. NextRunAt is not derived from the cron
+// expression; it is set one hour ahead.
+func (p *Panel) createSchedule(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req client.CreateScheduleRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeValidationError(w, "name", "The name field is required.")
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	sc := &models.Schedule{
+		ID:   p.newID(),
+		Name: req.Name,
+		Cron: models.Cron{
+			Minute:     req.Minute,
+			Hour:       req.Hour,
+			DayOfMonth: req.DayOfMonth,
+			Month:      req.Month,
+			DayOfWeek:  req.DayOfWeek,
+		},
+		IsActive:       req.IsActive,
+		OnlyWhenOnline: req.OnlyWhenOnline,
+		NextRunAt:      now.Add(time.Hour),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Tasks:          &[]models.ScheduleTask{},
+	}
+	rt.schedules = append(rt.schedules, sc)
+	writeJSON(w, http.StatusOK, item("server_schedule", sc))
+}
+
+func (p *Panel) updateSchedule(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req client.UpdateScheduleRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	_, sc := findSchedule(w, r, rt)
+	if sc == nil {
+		return
+	}
+	setString(&sc.Name, req.Name)
+	setString(&sc.Cron.Minute, req.Minute)
+	setString(&sc.Cron.Hour, req.Hour)
+	setString(&sc.Cron.DayOfMonth, req.DayOfMonth)
+	setString(&sc.Cron.Month, req.Month)
+	setString(&sc.Cron.DayOfWeek, req.DayOfWeek)
+	if req.IsActive != nil {
+		sc.IsActive = *req.IsActive
+	}
+	if req.OnlyWhenOnline != nil {
+		sc.OnlyWhenOnline = *req.OnlyWhenOnline
+	}
+	sc.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, item("server_schedule", sc))
+}
+
+func (p *Panel) deleteSchedule(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	i, sc := findSchedule(w, r, rt)
+	if sc == nil {
+		return
+	}
+	rt.schedules = append(rt.schedules[:i], rt.schedules[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// executeSchedule runs the tasks of a schedule in sequence, ignoring their time
+// offsets. Failing tasks are skipped.
+func (p *Panel) executeSchedule(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	s, rt, ok := p.resolveServerLocked(w, r, false)
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	_, sc := findSchedule(w, r, rt)
+	if sc == nil {
+		p.mu.Unlock()
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	sc.LastRunAt = &now
+	tasks := append([]models.ScheduleTask(nil), (*sc.Tasks)...)
+	identifier := s.Identifier
+	p.mu.Unlock()
+
+	for _, task := range tasks {
+		switch task.Action {
+		case "command":
+			p.mu.Lock()
+			if rt.state != "offline" {
+				rt.commands = append(rt.commands, task.Payload)
+			}
+			p.mu.Unlock()
+		case "power":
+			p.power(identifier, task.Payload)
+		case "backup":
+			p.mu.Lock()
+			createBackupLocked(s, rt, client.CreateBackupRequest{Ignored: task.Payload})
+			p.mu.Unlock()
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// findTask returns the task named by the {task} path value, writing a 404
+// response if it does not exist.
+func findTask(w http.ResponseWriter, r *http.Request, sc *models.Schedule) (int, *models.ScheduleTask) {
+	id, _ := pathID(r, "task")
+	for i := range *sc.Tasks {
+		if (*sc.Tasks)[i].ID == id {
+			return i, &(*sc.Tasks)[i]
+		}
+	}
+	writeNotFound(w)
+	return -1, nil
+}
+
+func (p *Panel) createTask(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req client.CreateScheduleTaskRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	_, sc := findSchedule(w, r, rt)
+	if sc == nil {
+		return
+	}
+	switch req.Action {
+	case "command", "power", "backup":
+	default:
+		writeValidationError(w, "action", "The selected action is invalid.")
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	task := models.ScheduleTask{
+		ID:                p.newID(),
+		SequenceID:        len(*sc.Tasks) + 1,
+		Action:            req.Action,
+		Payload:           req.Payload,
+		TimeOffset:        req.TimeOffset,
+		ContinueOnFailure: req.ContinueOnFailure,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	*sc.Tasks = append(*sc.Tasks, task)
+	writeJSON(w, http.StatusOK, item("schedule_task", task))
+}
+
+func (p *Panel) updateTask(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req client.UpdateScheduleTaskRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	_, sc := findSchedule(w, r, rt)
+	if sc == nil {
+		return
+	}
+	_, task := findTask(w, r, sc)
+	if task == nil {
+		return
+	}
+	setString(&task.Action, req.Action)
+	setString(&task.Payload, req.Payload)
+	if req.TimeOffset != 0 {
+		task.TimeOffset = req.TimeOffset
+	}
+	if req.ContinueOnFailure != nil {
+		task.ContinueOnFailure = *req.ContinueOnFailure
+	}
+	task.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, item("schedule_task", task))
+}
+
+func (p *Panel) deleteTask(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	_, sc := findSchedule(w, r, rt)
+	if sc == nil {
+		return
+	}
+	i, task := findTask(w, r, sc)
+	if task == nil {
+		return
+	}
+	*sc.Tasks = append((*sc.Tasks)[:i], (*sc.Tasks)[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Databases
+
+// withPassword returns a copy of a database including its password relationship.
+func withPassword(db *models.Database, password string) models.Database {
+	out := *db
+	out.Password = &struct {
+		Attributes models.DatabasePassword `json:"attributes"`
+	}{Attributes: models.DatabasePassword{Password: password}}
+	return out
+}
+
+func (p *Panel) listDatabases(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	writeJSON(w, http.StatusOK, list("server_database", rt.databases))
+}
+
+// createDatabase creates a database. Like the panel, a database limit of zero
+// disables databases.
+func (p *Panel) createDatabase(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req struct {
+		Database string `json:"database"`
+		Remote   string `json:"remote"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Database == "" {
+		writeValidationError(w, "database", "The database field is required.")
+		return
+	}
+	if len(rt.databases) >= s.FeatureLimits.Databases {
+		writeError(w, http.StatusBadRequest, "TooManyDatabasesException", "Cannot create additional databases on this server: limit has been reached.")
+		return
+	}
+	name := fmt.Sprintf("s%d_%s", s.ID, req.Database)
+	for _, db := range rt.databases {
+		if db.Name == name {
+			writeValidationError(w, "database", "The database name has already been taken.")
+			return
+		}
+	}
+	if req.Remote == "" {
+		req.Remote = "%"
+	}
+	db := &models.Database{
+		ID:              randomHex(4),
+		Host:            models.DatabaseHost{Address: "127.0.0.1", Port: 3306},
+		Name:            name,
+		Username:        fmt.Sprintf("u%d_%s", s.ID, randomHex(5)),
+		ConnectionsFrom: req.Remote,
+	}
+	rt.databases = append(rt.databases, db)
+	writeJSON(w, http.StatusOK, item("server_database", withPassword(db, randomHex(12))))
+}
+
+// findDatabase returns the database named by the {database} path value,
+// writing a 404 response if it does not exist.
+func findDatabase(w http.ResponseWriter, r *http.Request, rt *serverState) (int, *models.Database) {
+	for i, db := range rt.databases {
+		if db.ID == r.PathValue("database") {
+			return i, db
+		}
+	}
+	writeNotFound(w)
+	return -1, nil
+}
+
+func (p *Panel) rotateDatabasePassword(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	if _, db := findDatabase(w, r, rt); db != nil {
+		writeJSON(w, http.StatusOK, item("server_database", withPassword(db, randomHex(12))))
+	}
+}
+
+func (p *Panel) deleteDatabase(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	i, db := findDatabase(w, r, rt)
+	if db == nil {
+		return
+	}
+	rt.databases = append(rt.databases[:i], rt.databases[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Network
+
+// serverAllocationsLocked returns copies of the allocations assigned to a
+// server with IsDefault set on the primary one. p.mu must be held.
+func (p *Panel) serverAllocationsLocked(s *models.Server) []models.Allocation {
+	var out []models.Allocation
+	for _, a := range sortedValues(p.allocations) {
+		if a.ServerID == s.ID {
+			v := *a
+			v.IsDefault = a.ID == s.AllocationID
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (p *Panel) listServerAllocations(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	writeJSON(w, http.StatusOK, list("allocation", p.serverAllocationsLocked(s)))
+}
+
+// assignAllocation assigns a free allocation on the server's node, up to the
+// server's allocation limit.
+func (p *Panel) assignAllocation(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	if len(p.serverAllocationsLocked(s)) >= s.FeatureLimits.Allocations {
+		writeError(w, http.StatusBadRequest, "DisplayException", "Cannot assign additional allocations to this server: limit has been reached.")
+		return
+	}
+	for _, a := range sortedValues(p.allocations) {
+		if a.ServerID == 0 && p.allocationNodes[a.ID] == s.NodeID {
+			a.ServerID = s.ID
+			writeJSON(w, http.StatusOK, item("allocation", a))
+			return
+		}
+	}
+	writeError(w, http.StatusBadRequest, "NoAutoAllocationSpaceAvailableException", "Cannot assign additional allocation: no more space available on node.")
+}
+
+// findServerAllocation returns the allocation named by the {allocation} path
+// value if it is assigned to the server, writing a 404 response otherwise.
+func (p *Panel) findServerAllocation(w http.ResponseWriter, r *http.Request, s *models.Server) *models.Allocation {
+	id, _ := pathID(r, "allocation")
+	a, ok := p.allocations[id]
+	if !ok || a.ServerID != s.ID {
+		writeNotFound(w)
+		return nil
+	}
+	return a
+}
+
+func (p *Panel) updateAllocationNotes(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req struct {
+		Notes string `json:"notes"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if a := p.findServerAllocation(w, r, s); a != nil {
+		a.Notes = &req.Notes
+		writeJSON(w, http.StatusOK, item("allocation", a))
+	}
+}
+
+func (p *Panel) setPrimaryAllocation(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	if a := p.findServerAllocation(w, r, s); a != nil {
+		s.AllocationID = a.ID
+		writeJSON(w, http.StatusOK, item("allocation", a))
+	}
+}
+
+func (p *Panel) deleteServerAllocation(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	a := p.findServerAllocation(w, r, s)
+	if a == nil {
+		return
+	}
+	if a.ID == s.AllocationID {
+		writeError(w, http.StatusBadRequest, "DisplayException", "You cannot delete the primary allocation for this server.")
+		return
+	}
+	a.ServerID = 0
+	a.Notes = nil
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Settings
+
+func (p *Panel) renameServer(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeValidationError(w, "name", "The name field is required.")
+		return
+	}
+	s.Name = req.Name
+	s.Description = req.Description
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *Panel) reinstallClientServer(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (p *Panel) setDockerImage(w http.ResponseWriter, r *http.Request, s *models.Server, rt *serverState) {
+	var req struct {
+		DockerImage string `json:"docker_image"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	s.DockerImage = req.DockerImage
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,442 @@
+// Code generated by genfake. DO NOT EDIT.
+
+package ptest
+
+import (
+	"context"
+
+	"github.com/idanyas/go-pterodactyl/application"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/pagination"
+)
+
+// FakeApplicationClient is a programmable fake of application.ApplicationClient.
+// Each method calls the matching Func field if it is set. Otherwise it returns
+// zero values: pointers to zero structs, empty paginators, nil slices and a nil
+// error. Every call is recorded and can be inspected with Calls and CallsTo.
+type FakeApplicationClient struct {
+	recorder
+
+	ListUsersFunc                   func(ctx context.Context, options pagination.ListOptions) ([]*models.User, *pagination.Paginator[*models.User], error)
+	GetUserFunc                     func(ctx context.Context, id int) (*models.User, error)
+	GetUserExternalFunc             func(ctx context.Context, externalID string) (*models.User, error)
+	CreateUserFunc                  func(ctx context.Context, req application.CreateUserRequest) (*models.User, error)
+	UpdateUserFunc                  func(ctx context.Context, id int, req application.UpdateUserRequest) (*models.User, error)
+	DeleteUserFunc                  func(ctx context.Context, id int) error
+	ListServersFunc                 func(ctx context.Context, options pagination.ListOptions) ([]*models.Server, *pagination.Paginator[*models.Server], error)
+	GetServerFunc                   func(ctx context.Context, id int) (*models.Server, error)
+	GetServerExternalFunc           func(ctx context.Context, externalID string) (*models.Server, error)
+	CreateServerFunc                func(ctx context.Context, req application.CreateServerRequest) (*models.Server, error)
+	UpdateServerDetailsFunc         func(ctx context.Context, serverID int, req application.UpdateServerDetailsRequest) (*models.Server, error)
+	UpdateServerBuildFunc           func(ctx context.Context, serverID int, req application.UpdateServerBuildRequest) (*models.Server, error)
+	UpdateServerStartupFunc         func(ctx context.Context, serverID int, req application.UpdateServerStartupRequest) (*models.Server, error)
+	SuspendServerFunc               func(ctx context.Context, serverID int) error
+	UnsuspendServerFunc             func(ctx context.Context, serverID int) error
+	ReinstallServerFunc             func(ctx context.Context, serverID int) error
+	DeleteServerFunc                func(ctx context.Context, serverID int, force bool) error
+	ListServerDatabasesFunc         func(ctx context.Context, serverID int, options pagination.ListOptions) ([]*models.ApplicationDatabase, *pagination.Paginator[*models.ApplicationDatabase], error)
+	GetServerDatabaseFunc           func(ctx context.Context, serverID int, databaseID int) (*models.ApplicationDatabase, error)
+	CreateServerDatabaseFunc        func(ctx context.Context, serverID int, req application.CreateServerDatabaseRequest) (*models.ApplicationDatabase, error)
+	UpdateServerDatabaseFunc        func(ctx context.Context, serverID int, databaseID int, req application.UpdateServerDatabaseRequest) (*models.ApplicationDatabase, error)
+	ResetServerDatabasePasswordFunc func(ctx context.Context, serverID int, databaseID int) error
+	DeleteServerDatabaseFunc        func(ctx context.Context, serverID int, databaseID int) error
+	ListNodesFunc                   func(ctx context.Context, options pagination.ListOptions) ([]*models.Node, *pagination.Paginator[*models.Node], error)
+	GetNodeFunc                     func(ctx context.Context, id int) (*models.Node, error)
+	CreateNodeFunc                  func(ctx context.Context, req application.CreateNodeRequest) (*models.Node, error)
+	UpdateNodeFunc                  func(ctx context.Context, id int, req application.UpdateNodeRequest) (*models.Node, error)
+	DeleteNodeFunc                  func(ctx context.Context, id int) error
+	GetNodeConfigurationFunc        func(ctx context.Context, id int) (*models.NodeConfiguration, error)
+	ListNodeAllocationsFunc         func(ctx context.Context, nodeID int, options pagination.ListOptions) ([]*models.Allocation, *pagination.Paginator[*models.Allocation], error)
+	CreateNodeAllocationsFunc       func(ctx context.Context, nodeID int, req application.CreateNodeAllocationRequest) error
+	DeleteNodeAllocationFunc        func(ctx context.Context, nodeID int, allocationID int) error
+	GetDeployableNodesFunc          func(ctx context.Context, memory int64, disk int64) ([]*models.Node, error)
+	ListLocationsFunc               func(ctx context.Context, options pagination.ListOptions) ([]*models.Location, *pagination.Paginator[*models.Location], error)
+	GetLocationFunc                 func(ctx context.Context, id int) (*models.Location, error)
+	CreateLocationFunc              func(ctx context.Context, req application.CreateLocationRequest) (*models.Location, error)
+	UpdateLocationFunc              func(ctx context.Context, id int, req application.UpdateLocationRequest) (*models.Location, error)
+	DeleteLocationFunc              func(ctx context.Context, id int) error
+	ListNestsFunc                   func(ctx context.Context, options pagination.ListOptions) ([]*models.Nest, *pagination.Paginator[*models.Nest], error)
+	GetNestFunc                     func(ctx context.Context, id int) (*models.Nest, error)
+	ListNestEggsFunc                func(ctx context.Context, nestID int, options pagination.ListOptions) ([]*models.Egg, *pagination.Paginator[*models.Egg], error)
+	GetEggFunc                      func(ctx context.Context, nestID int, eggID int) (*models.Egg, error)
+}
+
+var _ application.ApplicationClient = (*FakeApplicationClient)(nil)
+
+// ListUsers calls ListUsersFunc if it is set.
+func (f *FakeApplicationClient) ListUsers(ctx context.Context, options pagination.ListOptions) ([]*models.User, *pagination.Paginator[*models.User], error) {
+	f.record("ListUsers", options)
+	if f.ListUsersFunc != nil {
+		return f.ListUsersFunc(ctx, options)
+	}
+	return nil, new(pagination.Paginator[*models.User]), nil
+}
+
+// GetUser calls GetUserFunc if it is set.
+func (f *FakeApplicationClient) GetUser(ctx context.Context, id int) (*models.User, error) {
+	f.record("GetUser", id)
+	if f.GetUserFunc != nil {
+		return f.GetUserFunc(ctx, id)
+	}
+	return new(models.User), nil
+}
+
+// GetUserExternal calls GetUserExternalFunc if it is set.
+func (f *FakeApplicationClient) GetUserExternal(ctx context.Context, externalID string) (*models.User, error) {
+	f.record("GetUserExternal", externalID)
+	if f.GetUserExternalFunc != nil {
+		return f.GetUserExternalFunc(ctx, externalID)
+	}
+	return new(models.User), nil
+}
+
+// CreateUser calls CreateUserFunc if it is set.
+func (f *FakeApplicationClient) CreateUser(ctx context.Context, req application.CreateUserRequest) (*models.User, error) {
+	f.record("CreateUser", req)
+	if f.CreateUserFunc != nil {
+		return f.CreateUserFunc(ctx, req)
+	}
+	return new(models.User), nil
+}
+
+// UpdateUser calls UpdateUserFunc if it is set.
+func (f *FakeApplicationClient) UpdateUser(ctx context.Context, id int, req application.UpdateUserRequest) (*models.User, error) {
+	f.record("UpdateUser", id, req)
+	if f.UpdateUserFunc != nil {
+		return f.UpdateUserFunc(ctx, id, req)
+	}
+	return new(models.User), nil
+}
+
+// DeleteUser calls DeleteUserFunc if it is set.
+func (f *FakeApplicationClient) DeleteUser(ctx context.Context, id int) error {
+	f.record("DeleteUser", id)
+	if f.DeleteUserFunc != nil {
+		return f.DeleteUserFunc(ctx, id)
+	}
+	return nil
+}
+
+// ListServers calls ListServersFunc if it is set.
+func (f *FakeApplicationClient) ListServers(ctx context.Context, options pagination.ListOptions) ([]*models.Server, *pagination.Paginator[*models.Server], error) {
+	f.record("ListServers", options)
+	if f.ListServersFunc != nil {
+		return f.ListServersFunc(ctx, options)
+	}
+	return nil, new(pagination.Paginator[*models.Server]), nil
+}
+
+// GetServer calls GetServerFunc if it is set.
+func (f *FakeApplicationClient) GetServer(ctx context.Context, id int) (*models.Server, error) {
+	f.record("GetServer", id)
+	if f.GetServerFunc != nil {
+		return f.GetServerFunc(ctx, id)
+	}
+	return new(models.Server), nil
+}
+
+// GetServerExternal calls GetServerExternalFunc if it is set.
+func (f *FakeApplicationClient) GetServerExternal(ctx context.Context, externalID string) (*models.Server, error) {
+	f.record("GetServerExternal", externalID)
+	if f.GetServerExternalFunc != nil {
+		return f.GetServerExternalFunc(ctx, externalID)
+	}
+	return new(models.Server), nil
+}
+
+// CreateServer calls CreateServerFunc if it is set.
+func (f *FakeApplicationClient) CreateServer(ctx context.Context, req application.CreateServerRequest) (*models.Server, error) {
+	f.record("CreateServer", req)
+	if f.CreateServerFunc != nil {
+		return f.CreateServerFunc(ctx, req)
+	}
+	return new(models.Server), nil
+}
+
+// UpdateServerDetails calls UpdateServerDetailsFunc if it is set.
+func (f *FakeApplicationClient) UpdateServerDetails(ctx context.Context, serverID int, req application.UpdateServerDetailsRequest) (*models.Server, error) {
+	f.record("UpdateServerDetails", serverID, req)
+	if f.UpdateServerDetailsFunc != nil {
+		return f.UpdateServerDetailsFunc(ctx, serverID, req)
+	}
+	return new(models.Server), nil
+}
+
+// UpdateServerBuild calls UpdateServerBuildFunc if it is set.
+func (f *FakeApplicationClient) UpdateServerBuild(ctx context.Context, serverID int, req application.UpdateServerBuildRequest) (*models.Server, error) {
+	f.record("UpdateServerBuild", serverID, req)
+	if f.UpdateServerBuildFunc != nil {
+		return f.UpdateServerBuildFunc(ctx, serverID, req)
+	}
+	return new(models.Server), nil
+}
+
+// UpdateServerStartup calls UpdateServerStartupFunc if it is set.
+func (f *FakeApplicationClient) UpdateServerStartup(ctx context.Context, serverID int, req application.UpdateServerStartupRequest) (*models.Server, error) {
+	f.record("UpdateServerStartup", serverID, req)
+	if f.UpdateServerStartupFunc != nil {
+		return f.UpdateServerStartupFunc(ctx, serverID, req)
+	}
+	return new(models.Server), nil
+}
+
+// SuspendServer calls SuspendServerFunc if it is set.
+func (f *FakeApplicationClient) SuspendServer(ctx context.Context, serverID int) error {
+	f.record("SuspendServer", serverID)
+	if f.SuspendServerFunc != nil {
+		return f.SuspendServerFunc(ctx, serverID)
+	}
+	return nil
+}
+
+// UnsuspendServer calls UnsuspendServerFunc if it is set.
+func (f *FakeApplicationClient) UnsuspendServer(ctx context.Context, serverID int) error {
+	f.record("UnsuspendServer", serverID)
+	if f.UnsuspendServerFunc != nil {
+		return f.UnsuspendServerFunc(ctx, serverID)
+	}
+	return nil
+}
+
+// ReinstallServer calls ReinstallServerFunc if it is set.
+func (f *FakeApplicationClient) ReinstallServer(ctx context.Context, serverID int) error {
+	f.record("ReinstallServer", serverID)
+	if f.ReinstallServerFunc != nil {
+		return f.ReinstallServerFunc(ctx, serverID)
+	}
+	return nil
+}
+
+// DeleteServer calls DeleteServerFunc if it is set.
+func (f *FakeApplicationClient) DeleteServer(ctx context.Context, serverID int, force bool) error {
+	f.record("DeleteServer", serverID, force)
+	if f.DeleteServerFunc != nil {
+		return f.DeleteServerFunc(ctx, serverID, force)
+	}
+	return nil
+}
+
+// ListServerDatabases calls ListServerDatabasesFunc if it is set.
+func (f *FakeApplicationClient) ListServerDatabases(ctx context.Context, serverID int, options pagination.ListOptions) ([]*models.ApplicationDatabase, *pagination.Paginator[*models.ApplicationDatabase], error) {
+	f.record("ListServerDatabases", serverID, options)
+	if f.ListServerDatabasesFunc != nil {
+		return f.ListServerDatabasesFunc(ctx, serverID, options)
+	}
+	return nil, new(pagination.Paginator[*models.ApplicationDatabase]), nil
+}
+
+// GetServerDatabase calls GetServerDatabaseFunc if it is set.
+func (f *FakeApplicationClient) GetServerDatabase(ctx context.Context, serverID int, databaseID int) (*models.ApplicationDatabase, error) {
+	f.record("GetServerDatabase", serverID, databaseID)
+	if f.GetServerDatabaseFunc != nil {
+		return f.GetServerDatabaseFunc(ctx, serverID, databaseID)
+	}
+	return new(models.ApplicationDatabase), nil
+}
+
+// CreateServerDatabase calls CreateServerDatabaseFunc if it is set.
+func (f *FakeApplicationClient) CreateServerDatabase(ctx context.Context, serverID int, req application.CreateServerDatabaseRequest) (*models.ApplicationDatabase, error) {
+	f.record("CreateServerDatabase", serverID, req)
+	if f.CreateServerDatabaseFunc != nil {
+		return f.CreateServerDatabaseFunc(ctx, serverID, req)
+	}
+	return new(models.ApplicationDatabase), nil
+}
+
+// UpdateServerDatabase calls UpdateServerDatabaseFunc if it is set.
+func (f *FakeApplicationClient) UpdateServerDatabase(ctx context.Context, serverID int, databaseID int, req application.UpdateServerDatabaseRequest) (*models.ApplicationDatabase, error) {
+	f.record("UpdateServerDatabase", serverID, databaseID, req)
+	if f.UpdateServerDatabaseFunc != nil {
+		return f.UpdateServerDatabaseFunc(ctx, serverID, databaseID, req)
+	}
+	return new(models.ApplicationDatabase), nil
+}
+
+// ResetServerDatabasePassword calls ResetServerDatabasePasswordFunc if it is set.
+func (f *FakeApplicationClient) ResetServerDatabasePassword(ctx context.Context, serverID int, databaseID int) error {
+	f.record("ResetServerDatabasePassword", serverID, databaseID)
+	if f.ResetServerDatabasePasswordFunc != nil {
+		return f.ResetServerDatabasePasswordFunc(ctx, serverID, databaseID)
+	}
+	return nil
+}
+
+// DeleteServerDatabase calls DeleteServerDatabaseFunc if it is set.
+func (f *FakeApplicationClient) DeleteServerDatabase(ctx context.Context, serverID int, databaseID int) error {
+	f.record("DeleteServerDatabase", serverID, databaseID)
+	if f.DeleteServerDatabaseFunc != nil {
+		return f.DeleteServerDatabaseFunc(ctx, serverID, databaseID)
+	}
+	return nil
+}
+
+// ListNodes calls ListNodesFunc if it is set.
+func (f *FakeApplicationClient) ListNodes(ctx context.Context, options pagination.ListOptions) ([]*models.Node, *pagination.Paginator[*models.Node], error) {
+	f.record("ListNodes", options)
+	if f.ListNodesFunc != nil {
+		return f.ListNodesFunc(ctx, options)
+	}
+	return nil, new(pagination.Paginator[*models.Node]), nil
+}
+
+// GetNode calls GetNodeFunc if it is set.
+func (f *FakeApplicationClient) GetNode(ctx context.Context, id int) (*models.Node, error) {
+	f.record("GetNode", id)
+	if f.GetNodeFunc != nil {
+		return f.GetNodeFunc(ctx, id)
+	}
+	return new(models.Node), nil
+}
+
+// CreateNode calls CreateNodeFunc if it is set.
+func (f *FakeApplicationClient) CreateNode(ctx context.Context, req application.CreateNodeRequest) (*models.Node, error) {
+	f.record("CreateNode", req)
+	if f.CreateNodeFunc != nil {
+		return f.CreateNodeFunc(ctx, req)
+	}
+	return new(models.Node), nil
+}
+
+// UpdateNode calls UpdateNodeFunc if it is set.
+func (f *FakeApplicationClient) UpdateNode(ctx context.Context, id int, req application.UpdateNodeRequest) (*models.Node, error) {
+	f.record("UpdateNode", id, req)
+	if f.UpdateNodeFunc != nil {
+		return f.UpdateNodeFunc(ctx, id, req)
+	}
+	return new(models.Node), nil
+}
+
+// DeleteNode calls DeleteNodeFunc if it is set.
+func (f *FakeApplicationClient) DeleteNode(ctx context.Context, id int) error {
+	f.record("DeleteNode", id)
+	if f.DeleteNodeFunc != nil {
+		return f.DeleteNodeFunc(ctx, id)
+	}
+	return nil
+}
+
+// GetNodeConfiguration calls GetNodeConfigurationFunc if it is set.
+func (f *FakeApplicationClient) GetNodeConfiguration(ctx context.Context, id int) (*models.NodeConfiguration, error) {
+	f.record("GetNodeConfiguration", id)
+	if f.GetNodeConfigurationFunc != nil {
+		return f.GetNodeConfigurationFunc(ctx, id)
+	}
+	return new(models.NodeConfiguration), nil
+}
+
+// ListNodeAllocations calls ListNodeAllocationsFunc if it is set.
+func (f *FakeApplicationClient) ListNodeAllocations(ctx context.Context, nodeID int, options pagination.ListOptions) ([]*models.Allocation, *pagination.Paginator[*models.Allocation], error) {
+	f.record("ListNodeAllocations", nodeID, options)
+	if f.ListNodeAllocationsFunc != nil {
+		return f.ListNodeAllocationsFunc(ctx, nodeID, options)
+	}
+	return nil, new(pagination.Paginator[*models.Allocation]), nil
+}
+
+// CreateNodeAllocations calls CreateNodeAllocationsFunc if it is set.
+func (f *FakeApplicationClient) CreateNodeAllocations(ctx context.Context, nodeID int, req application.CreateNodeAllocationRequest) error {
+	f.record("CreateNodeAllocations", nodeID, req)
+	if f.CreateNodeAllocationsFunc != nil {
+		return f.CreateNodeAllocationsFunc(ctx, nodeID, req)
+	}
+	return nil
+}
+
+// DeleteNodeAllocation calls DeleteNodeAllocationFunc if it is set.
+func (f *FakeApplicationClient) DeleteNodeAllocation(ctx context.Context, nodeID int, allocationID int) error {
+	f.record("DeleteNodeAllocation", nodeID, allocationID)
+	if f.DeleteNodeAllocationFunc != nil {
+		return f.DeleteNodeAllocationFunc(ctx, nodeID, allocationID)
+	}
+	return nil
+}
+
+// GetDeployableNodes calls GetDeployableNodesFunc if it is set.
+func (f *FakeApplicationClient) GetDeployableNodes(ctx context.Context, memory int64, disk int64) ([]*models.Node, error) {
+	f.record("GetDeployableNodes", memory, disk)
+	if f.GetDeployableNodesFunc != nil {
+		return f.GetDeployableNodesFunc(ctx, memory, disk)
+	}
+	return nil, nil
+}
+
+// ListLocations calls ListLocationsFunc if it is set.
+func (f *FakeApplicationClient) ListLocations(ctx context.Context, options pagination.ListOptions) ([]*models.Location, *pagination.Paginator[*models.Location], error) {
+	f.record("ListLocations", options)
+	if f.ListLocationsFunc != nil {
+		return f.ListLocationsFunc(ctx, options)
+	}
+	return nil, new(pagination.Paginator[*models.Location]), nil
+}
+
+// GetLocation calls GetLocationFunc if it is set.
+func (f *FakeApplicationClient) GetLocation(ctx context.Context, id int) (*models.Location, error) {
+	f.record("GetLocation", id)
+	if f.GetLocationFunc != nil {
+		return f.GetLocationFunc(ctx, id)
+	}
+	return new(models.Location), nil
+}
+
+// CreateLocation calls CreateLocationFunc if it is set.
+func (f *FakeApplicationClient) CreateLocation(ctx context.Context, req application.CreateLocationRequest) (*models.Location, error) {
+	f.record("CreateLocation", req)
+	if f.CreateLocationFunc != nil {
+		return f.CreateLocationFunc(ctx, req)
+	}
+	return new(models.Location), nil
+}
+
+// UpdateLocation calls UpdateLocationFunc if it is set.
+func (f *FakeApplicationClient) UpdateLocation(ctx context.Context, id int, req application.UpdateLocationRequest) (*models.Location, error) {
+	f.record("UpdateLocation", id, req)
+	if f.UpdateLocationFunc != nil {
+		return f.UpdateLocationFunc(ctx, id, req)
+	}
+	return new(models.Location), nil
+}
+
+// DeleteLocation calls DeleteLocationFunc if it is set.
+func (f *FakeApplicationClient) DeleteLocation(ctx context.Context, id int) error {
+	f.record("DeleteLocation", id)
+	if f.DeleteLocationFunc != nil {
+		return f.DeleteLocationFunc(ctx, id)
+	}
+	return nil
+}
+
+// ListNests calls ListNestsFunc if it is set.
+func (f *FakeApplicationClient) ListNests(ctx context.Context, options pagination.ListOptions) ([]*models.Nest, *pagination.Paginator[*models.Nest], error) {
+	f.record("ListNests", options)
+	if f.ListNestsFunc != nil {
+		return f.ListNestsFunc(ctx, options)
+	}
+	return nil, new(pagination.Paginator[*models.Nest]), nil
+}
+
+// GetNest calls GetNestFunc if it is set.
+func (f *FakeApplicationClient) GetNest(ctx context.Context, id int) (*models.Nest, error) {
+	f.record("GetNest", id)
+	if f.GetNestFunc != nil {
+		return f.GetNestFunc(ctx, id)
+	}
+	return new(models.Nest), nil
+}
+
+// ListNestEggs calls ListNestEggsFunc if it is set.
+func (f *FakeApplicationClient) ListNestEggs(ctx context.Context, nestID int, options pagination.ListOptions) ([]*models.Egg, *pagination.Paginator[*models.Egg], error) {
+	f.record("ListNestEggs", nestID, options)
+	if f.ListNestEggsFunc != nil {
+		return f.ListNestEggsFunc(ctx, nestID, options)
+	}
+	return nil, new(pagination.Paginator[*models.Egg]), nil
+}
+
+// GetEgg calls GetEggFunc if it is set.
+func (f *FakeApplicationClient) GetEgg(ctx context.Context, nestID int, eggID int) (*models.Egg, error) {
+	f.record("GetEgg", nestID, eggID)
+	if f.GetEggFunc != nil {
+		return f.GetEggFunc(ctx, nestID, eggID)
+	}
+	return new(models.Egg), nil
+}
@@ -0,0 +1,713 @@
+// Code generated by genfake. DO NOT EDIT.
+
+package ptest
+
+import (
+	"context"
+
+	"github.com/idanyas/go-pterodactyl/client"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/pagination"
+	"github.com/idanyas/go-pterodactyl/websocket"
+)
+
+// FakeClientClient is a programmable fake of client.ClientClient.
+// Each method calls the matching Func field if it is set. Otherwise it returns
+// zero values: pointers to zero structs, empty paginators, nil slices and a nil
+// error. Every call is recorded and can be inspected with Calls and CallsTo.
+type FakeClientClient struct {
+	recorder
+
+	GetAccountFunc             func(ctx context.Context) (*models.User, error)
+	GetTwoFactorQRFunc         func(ctx context.Context) (*models.TwoFactorData, error)
+	EnableTwoFactorFunc        func(ctx context.Context, code string) (*models.RecoveryTokens, error)
+	DisableTwoFactorFunc       func(ctx context.Context, password string) error
+	UpdateEmailFunc            func(ctx context.Context, email string, password string) error
+	UpdatePasswordFunc         func(ctx context.Context, currentPassword string, newPassword string, confirmPassword string) error
+	ListAPIKeysFunc            func(ctx context.Context) ([]*models.APIKey, error)
+	CreateAPIKeyFunc           func(ctx context.Context, description string, allowedIPs []string) (*models.APIKey, error)
+	DeleteAPIKeyFunc           func(ctx context.Context, identifier string) error
+	ListSSHKeysFunc            func(ctx context.Context) ([]*models.SSHKey, error)
+	AddSSHKeyFunc              func(ctx context.Context, name string, publicKey string) (*models.SSHKey, error)
+	RemoveSSHKeyFunc           func(ctx context.Context, fingerprint string) error
+	ListAccountActivityFunc    func(ctx context.Context, options pagination.ListOptions) ([]*models.ActivityLog, *pagination.Paginator[*models.ActivityLog], error)
+	ListServerActivityFunc     func(ctx context.Context, serverID string, options pagination.ListOptions) ([]*models.ActivityLog, *pagination.Paginator[*models.ActivityLog], error)
+	GetSystemPermissionsFunc   func(ctx context.Context) (*models.SystemPermissions, error)
+	ListServersFunc            func(ctx context.Context, options pagination.ListOptions) ([]*models.Server, *pagination.Paginator[*models.Server], error)
+	GetServerFunc              func(ctx context.Context, serverID string) (*models.Server, error)
+	GetServerResourcesFunc     func(ctx context.Context, serverID string) (*models.Stats, error)
+	SendPowerActionFunc        func(ctx context.Context, serverID string, signal string) error
+	SendCommandFunc            func(ctx context.Context, serverID string, command string) error
+	ConnectWebSocketFunc       func(ctx context.Context, serverID string) (*websocket.Conn, error)
+	ListFilesFunc              func(ctx context.Context, serverID string, directory string) ([]*models.FileObject, error)
+	GetFileContentsFunc        func(ctx context.Context, serverID string, filePath string) (string, error)
+	WriteFileFunc              func(ctx context.Context, serverID string, filePath string, content string) error
+	CreateDirectoryFunc        func(ctx context.Context, serverID string, root string, name string) error
+	DeleteFilesFunc            func(ctx context.Context, serverID string, root string, files []string) error
+	RenameFileFunc             func(ctx context.Context, serverID string, root string, from string, to string) error
+	CopyFileFunc               func(ctx context.Context, serverID string, location string) error
+	GetDownloadURLFunc         func(ctx context.Context, serverID string, filePath string) (*models.SignedURL, error)
+	GetUploadURLFunc           func(ctx context.Context, serverID string, directory string) (*models.SignedURL, error)
+	CompressFilesFunc          func(ctx context.Context, serverID string, root string, files []string) (*models.FileObject, error)
+	DecompressFileFunc         func(ctx context.Context, serverID string, root string, file string) error
+	ChmodFilesFunc             func(ctx context.Context, serverID string, root string, files []client.ChmodFileRequest) error
+	PullFileFunc               func(ctx context.Context, serverID string, url string, directory string, filename string) error
+	ListDatabasesFunc          func(ctx context.Context, serverID string) ([]*models.Database, error)
+	CreateDatabaseFunc         func(ctx context.Context, serverID string, database string, remote string) (*models.Database, error)
+	RotateDatabasePasswordFunc func(ctx context.Context, serverID string, databaseID string) (*models.Database, error)
+	DeleteDatabaseFunc         func(ctx context.Context, serverID string, databaseID string) error
+	ListBackupsFunc            func(ctx context.Context, serverID string) ([]*models.Backup, error)
+	GetBackupFunc              func(ctx context.Context, serverID string, backupUUID string) (*models.Backup, error)
+	CreateBackupFunc           func(ctx context.Context, serverID string, req client.CreateBackupRequest) (*models.Backup, error)
+	GetBackupDownloadURLFunc   func(ctx context.Context, serverID string, backupUUID string) (*models.SignedURL, error)
+	RestoreBackupFunc          func(ctx context.Context, serverID string, backupUUID string, truncate bool) error
+	ToggleBackupLockFunc       func(ctx context.Context, serverID string, backupUUID string) error
+	DeleteBackupFunc           func(ctx context.Context, serverID string, backupUUID string) error
+	GetStartupConfigFunc       func(ctx context.Context, serverID string) (*models.StartupConfiguration, error)
+	UpdateStartupVariableFunc  func(ctx context.Context, serverID string, key string, value string) (*models.StartupVariable, error)
+	RenameServerFunc           func(ctx context.Context, serverID string, name string, description string) error
+	ReinstallServerFunc        func(ctx context.Context, serverID string) error
+	UpdateDockerImageFunc      func(ctx context.Context, serverID string, dockerImage string) error
+	ListAllocationsFunc        func(ctx context.Context, serverID string) ([]*models.Allocation, error)
+	AssignAllocationFunc       func(ctx context.Context, serverID string) error
+	SetPrimaryAllocationFunc   func(ctx context.Context, serverID string, allocationID int) error
+	UpdateAllocationNotesFunc  func(ctx context.Context, serverID string, allocationID int, notes string) error
+	DeleteAllocationFunc       func(ctx context.Context, serverID string, allocationID int) error
+	ListSubusersFunc           func(ctx context.Context, serverID string) ([]*models.Subuser, error)
+	GetSubuserFunc             func(ctx context.Context, serverID string, userUUID string) (*models.Subuser, error)
+	CreateSubuserFunc          func(ctx context.Context, serverID string, email string, permissions []string) (*models.Subuser, error)
+	UpdateSubuserFunc          func(ctx context.Context, serverID string, userUUID string, permissions []string) (*models.Subuser, error)
+	DeleteSubuserFunc          func(ctx context.Context, serverID string, userUUID string) error
+	ListSchedulesFunc          func(ctx context.Context, serverID string) ([]*models.Schedule, error)
+	GetScheduleFunc            func(ctx context.Context, serverID string, scheduleID int) (*models.Schedule, error)
+	CreateScheduleFunc         func(ctx context.Context, serverID string, req client.CreateScheduleRequest) (*models.Schedule, error)
+	UpdateScheduleFunc         func(ctx context.Context, serverID string, scheduleID int, req client.UpdateScheduleRequest) (*models.Schedule, error)
+	DeleteScheduleFunc         func(ctx context.Context, serverID string, scheduleID int) error
+	ExecuteScheduleFunc        func(ctx context.Context, serverID string, scheduleID int) error
+	CreateScheduleTaskFunc     func(ctx context.Context, serverID string, scheduleID int, req client.CreateScheduleTaskRequest) (*models.ScheduleTask, error)
+	UpdateScheduleTaskFunc     func(ctx context.Context, serverID string, scheduleID int, taskID int, req client.UpdateScheduleTaskRequest) (*models.ScheduleTask, error)
+	DeleteScheduleTaskFunc     func(ctx context.Context, serverID string, scheduleID int, taskID int) error
+}
+
+var _ client.ClientClient = (*FakeClientClient)(nil)
+
+// GetAccount calls GetAccountFunc if it is set.
+func (f *FakeClientClient) GetAccount(ctx context.Context) (*models.User, error) {
+	f.record("GetAccount")
+	if f.GetAccountFunc != nil {
+		return f.GetAccountFunc(ctx)
+	}
+	return new(models.User), nil
+}
+
+// GetTwoFactorQR calls GetTwoFactorQRFunc if it is set.
+func (f *FakeClientClient) GetTwoFactorQR(ctx context.Context) (*models.TwoFactorData, error) {
+	f.record("GetTwoFactorQR")
+	if f.GetTwoFactorQRFunc != nil {
+		return f.GetTwoFactorQRFunc(ctx)
+	}
+	return new(models.TwoFactorData), nil
+}
+
+// EnableTwoFactor calls EnableTwoFactorFunc if it is set.
+func (f *FakeClientClient) EnableTwoFactor(ctx context.Context, code string) (*models.RecoveryTokens, error) {
+	f.record("EnableTwoFactor", code)
+	if f.EnableTwoFactorFunc != nil {
+		return f.EnableTwoFactorFunc(ctx, code)
+	}
+	return new(models.RecoveryTokens), nil
+}
+
+// DisableTwoFactor calls DisableTwoFactorFunc if it is set.
+func (f *FakeClientClient) DisableTwoFactor(ctx context.Context, password string) error {
+	f.record("DisableTwoFactor", password)
+	if f.DisableTwoFactorFunc != nil {
+		return f.DisableTwoFactorFunc(ctx, password)
+	}
+	return nil
+}
+
+// UpdateEmail calls UpdateEmailFunc if it is set.
+func (f *FakeClientClient) UpdateEmail(ctx context.Context, email string, password string) error {
+	f.record("UpdateEmail", email, password)
+	if f.UpdateEmailFunc != nil {
+		return f.UpdateEmailFunc(ctx, email, password)
+	}
+	return nil
+}
+
+// UpdatePassword calls UpdatePasswordFunc if it is set.
+func (f *FakeClientClient) UpdatePassword(ctx context.Context, currentPassword string, newPassword string, confirmPassword string) error {
+	f.record("UpdatePassword", currentPassword, newPassword, confirmPassword)
+	if f.UpdatePasswordFunc != nil {
+		return f.UpdatePasswordFunc(ctx, currentPassword, newPassword, confirmPassword)
+	}
+	return nil
+}
+
+// ListAPIKeys calls ListAPIKeysFunc if it is set.
+func (f *FakeClientClient) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	f.record("ListAPIKeys")
+	if f.ListAPIKeysFunc != nil {
+		return f.ListAPIKeysFunc(ctx)
+	}
+	return nil, nil
+}
+
+// CreateAPIKey calls CreateAPIKeyFunc if it is set.
+func (f *FakeClientClient) CreateAPIKey(ctx context.Context, description string, allowedIPs []string) (*models.APIKey, error) {
+	f.record("CreateAPIKey", description, allowedIPs)
+	if f.CreateAPIKeyFunc != nil {
+		return f.CreateAPIKeyFunc(ctx, description, allowedIPs)
+	}
+	return new(models.APIKey), nil
+}
+
+// DeleteAPIKey calls DeleteAPIKeyFunc if it is set.
+func (f *FakeClientClient) DeleteAPIKey(ctx context.Context, identifier string) error {
+	f.record("DeleteAPIKey", identifier)
+	if f.DeleteAPIKeyFunc != nil {
+		return f.DeleteAPIKeyFunc(ctx, identifier)
+	}
+	return nil
+}
+
+// ListSSHKeys calls ListSSHKeysFunc if it is set.
+func (f *FakeClientClient) ListSSHKeys(ctx context.Context) ([]*models.SSHKey, error) {
+	f.record("ListSSHKeys")
+	if f.ListSSHKeysFunc != nil {
+		return f.ListSSHKeysFunc(ctx)
+	}
+	return nil, nil
+}
+
+// AddSSHKey calls AddSSHKeyFunc if it is set.
+func (f *FakeClientClient) AddSSHKey(ctx context.Context, name string, publicKey string) (*models.SSHKey, error) {
+	f.record("AddSSHKey", name, publicKey)
+	if f.AddSSHKeyFunc != nil {
+		return f.AddSSHKeyFunc(ctx, name, publicKey)
+	}
+	return new(models.SSHKey), nil
+}
+
+// RemoveSSHKey calls RemoveSSHKeyFunc if it is set.
+func (f *FakeClientClient) RemoveSSHKey(ctx context.Context, fingerprint string) error {
+	f.record("RemoveSSHKey", fingerprint)
+	if f.RemoveSSHKeyFunc != nil {
+		return f.RemoveSSHKeyFunc(ctx, fingerprint)
+	}
+	return nil
+}
+
+// ListAccountActivity calls ListAccountActivityFunc if it is set.
+func (f *FakeClientClient) ListAccountActivity(ctx context.Context, options pagination.ListOptions) ([]*models.ActivityLog, *pagination.Paginator[*models.ActivityLog], error) {
+	f.record("ListAccountActivity", options)
+	if f.ListAccountActivityFunc != nil {
+		return f.ListAccountActivityFunc(ctx, options)
+	}
+	return nil, new(pagination.Paginator[*models.ActivityLog]), nil
+}
+
+// ListServerActivity calls ListServerActivityFunc if it is set.
+func (f *FakeClientClient) ListServerActivity(ctx context.Context, serverID string, options pagination.ListOptions) ([]*models.ActivityLog, *pagination.Paginator[*models.ActivityLog], error) {
+	f.record("ListServerActivity", serverID, options)
+	if f.ListServerActivityFunc != nil {
+		return f.ListServerActivityFunc(ctx, serverID, options)
+	}
+	return nil, new(pagination.Paginator[*models.ActivityLog]), nil
+}
+
+// GetSystemPermissions calls GetSystemPermissionsFunc if it is set.
+func (f *FakeClientClient) GetSystemPermissions(ctx context.Context) (*models.SystemPermissions, error) {
+	f.record("GetSystemPermissions")
+	if f.GetSystemPermissionsFunc != nil {
+		return f.GetSystemPermissionsFunc(ctx)
+	}
+	return new(models.SystemPermissions), nil
+}
+
+// ListServers calls ListServersFunc if it is set.
+func (f *FakeClientClient) ListServers(ctx context.Context, options pagination.ListOptions) ([]*models.Server, *pagination.Paginator[*models.Server], error) {
+	f.record("ListServers", options)
+	if f.ListServersFunc != nil {
+		return f.ListServersFunc(ctx, options)
+	}
+	return nil, new(pagination.Paginator[*models.Server]), nil
+}
+
+// GetServer calls GetServerFunc if it is set.
+func (f *FakeClientClient) GetServer(ctx context.Context, serverID string) (*models.Server, error) {
+	f.record("GetServer", serverID)
+	if f.GetServerFunc != nil {
+		return f.GetServerFunc(ctx, serverID)
+	}
+	return new(models.Server), nil
+}
+
+// GetServerResources calls GetServerResourcesFunc if it is set.
+func (f *FakeClientClient) GetServerResources(ctx context.Context, serverID string) (*models.Stats, error) {
+	f.record("GetServerResources", serverID)
+	if f.GetServerResourcesFunc != nil {
+		return f.GetServerResourcesFunc(ctx, serverID)
+	}
+	return new(models.Stats), nil
+}
+
+// SendPowerAction calls SendPowerActionFunc if it is set.
+func (f *FakeClientClient) SendPowerAction(ctx context.Context, serverID string, signal string) error {
+	f.record("SendPowerAction", serverID, signal)
+	if f.SendPowerActionFunc != nil {
+		return f.SendPowerActionFunc(ctx, serverID, signal)
+	}
+	return nil
+}
+
+// SendCommand calls SendCommandFunc if it is set.
+func (f *FakeClientClient) SendCommand(ctx context.Context, serverID string, command string) error {
+	f.record("SendCommand", serverID, command)
+	if f.SendCommandFunc != nil {
+		return f.SendCommandFunc(ctx, serverID, command)
+	}
+	return nil
+}
+
+// ConnectWebSocket calls ConnectWebSocketFunc if it is set.
+func (f *FakeClientClient) ConnectWebSocket(ctx context.Context, serverID string) (*websocket.Conn, error) {
+	f.record("ConnectWebSocket", serverID)
+	if f.ConnectWebSocketFunc != nil {
+		return f.ConnectWebSocketFunc(ctx, serverID)
+	}
+	return nil, ErrNotConfigured
+}
+
+// ListFiles calls ListFilesFunc if it is set.
+func (f *FakeClientClient) ListFiles(ctx context.Context, serverID string, directory string) ([]*models.FileObject, error) {
+	f.record("ListFiles", serverID, directory)
+	if f.ListFilesFunc != nil {
+		return f.ListFilesFunc(ctx, serverID, directory)
+	}
+	return nil, nil
+}
+
+// GetFileContents calls GetFileContentsFunc if it is set.
+func (f *FakeClientClient) GetFileContents(ctx context.Context, serverID string, filePath string) (string, error) {
+	f.record("GetFileContents", serverID, filePath)
+	if f.GetFileContentsFunc != nil {
+		return f.GetFileContentsFunc(ctx, serverID, filePath)
+	}
+	return "", nil
+}
+
+// WriteFile calls WriteFileFunc if it is set.
+func (f *FakeClientClient) WriteFile(ctx context.Context, serverID string, filePath string, content string) error {
+	f.record("WriteFile", serverID, filePath, content)
+	if f.WriteFileFunc != nil {
+		return f.WriteFileFunc(ctx, serverID, filePath, content)
+	}
+	return nil
+}
+
+// CreateDirectory calls CreateDirectoryFunc if it is set.
+func (f *FakeClientClient) CreateDirectory(ctx context.Context, serverID string, root string, name string) error {
+	f.record("CreateDirectory", serverID, root, name)
+	if f.CreateDirectoryFunc != nil {
+		return f.CreateDirectoryFunc(ctx, serverID, root, name)
+	}
+	return nil
+}
+
+// DeleteFiles calls DeleteFilesFunc if it is set.
+func (f *FakeClientClient) DeleteFiles(ctx context.Context, serverID string, root string, files []string) error {
+	f.record("DeleteFiles", serverID, root, files)
+	if f.DeleteFilesFunc != nil {
+		return f.DeleteFilesFunc(ctx, serverID, root, files)
+	}
+	return nil
+}
+
+// RenameFile calls RenameFileFunc if it is set.
+func (f *FakeClientClient) RenameFile(ctx context.Context, serverID string, root string, from string, to string) error {
+	f.record("RenameFile", serverID, root, from, to)
+	if f.RenameFileFunc != nil {
+		return f.RenameFileFunc(ctx, serverID, root, from, to)
+	}
+	return nil
+}
+
+// CopyFile calls CopyFileFunc if it is set.
+func (f *FakeClientClient) CopyFile(ctx context.Context, serverID string, location string) error {
+	f.record("CopyFile", serverID, location)
+	if f.CopyFileFunc != nil {
+		return f.CopyFileFunc(ctx, serverID, location)
+	}
+	return nil
+}
+
+// GetDownloadURL calls GetDownloadURLFunc if it is set.
+func (f *FakeClientClient) GetDownloadURL(ctx context.Context, serverID string, filePath string) (*models.SignedURL, error) {
+	f.record("GetDownloadURL", serverID, filePath)
+	if f.GetDownloadURLFunc != nil {
+		return f.GetDownloadURLFunc(ctx, serverID, filePath)
+	}
+	return new(models.SignedURL), nil
+}
+
+// GetUploadURL calls GetUploadURLFunc if it is set.
+func (f *FakeClientClient) GetUploadURL(ctx context.Context, serverID string, directory string) (*models.SignedURL, error) {
+	f.record("GetUploadURL", serverID, directory)
+	if f.GetUploadURLFunc != nil {
+		return f.GetUploadURLFunc(ctx, serverID, directory)
+	}
+	return new(models.SignedURL), nil
+}
+
+// CompressFiles calls CompressFilesFunc if it is set.
+func (f *FakeClientClient) CompressFiles(ctx context.Context, serverID string, root string, files []string) (*models.FileObject, error) {
+	f.record("CompressFiles", serverID, root, files)
+	if f.CompressFilesFunc != nil {
+		return f.CompressFilesFunc(ctx, serverID, root, files)
+	}
+	return new(models.FileObject), nil
+}
+
+// DecompressFile calls DecompressFileFunc if it is set.
+func (f *FakeClientClient) DecompressFile(ctx context.Context, serverID string, root string, file string) error {
+	f.record("DecompressFile", serverID, root, file)
+	if f.DecompressFileFunc != nil {
+		return f.DecompressFileFunc(ctx, serverID, root, file)
+	}
+	return nil
+}
+
+// ChmodFiles calls ChmodFilesFunc if it is set.
+func (f *FakeClientClient) ChmodFiles(ctx context.Context, serverID string, root string, files []client.ChmodFileRequest) error {
+	f.record("ChmodFiles", serverID, root, files)
+	if f.ChmodFilesFunc != nil {
+		return f.ChmodFilesFunc(ctx, serverID, root, files)
+	}
+	return nil
+}
+
+// PullFile calls PullFileFunc if it is set.
+func (f *FakeClientClient) PullFile(ctx context.Context, serverID string, url string, directory string, filename string) error {
+	f.record("PullFile", serverID, url, directory, filename)
+	if f.PullFileFunc != nil {
+		return f.PullFileFunc(ctx, serverID, url, directory, filename)
+	}
+	return nil
+}
+
+// ListDatabases calls ListDatabasesFunc if it is set.
+func (f *FakeClientClient) ListDatabases(ctx context.Context, serverID string) ([]*models.Database, error) {
+	f.record("ListDatabases", serverID)
+	if f.ListDatabasesFunc != nil {
+		return f.ListDatabasesFunc(ctx, serverID)
+	}
+	return nil, nil
+}
+
+// CreateDatabase calls CreateDatabaseFunc if it is set.
+func (f *FakeClientClient) CreateDatabase(ctx context.Context, serverID string, database string, remote string) (*models.Database, error) {
+	f.record("CreateDatabase", serverID, database, remote)
+	if f.CreateDatabaseFunc != nil {
+		return f.CreateDatabaseFunc(ctx, serverID, database, remote)
+	}
+	return new(models.Database), nil
+}
+
+// RotateDatabasePassword calls RotateDatabasePasswordFunc if it is set.
+func (f *FakeClientClient) RotateDatabasePassword(ctx context.Context, serverID string, databaseID string) (*models.Database, error) {
+	f.record("RotateDatabasePassword", serverID, databaseID)
+	if f.RotateDatabasePasswordFunc != nil {
+		return f.RotateDatabasePasswordFunc(ctx, serverID, databaseID)
+	}
+	return new(models.Database), nil
+}
+
+// DeleteDatabase calls DeleteDatabaseFunc if it is set.
+func (f *FakeClientClient) DeleteDatabase(ctx context.Context, serverID string, databaseID string) error {
+	f.record("DeleteDatabase", serverID, databaseID)
+	if f.DeleteDatabaseFunc != nil {
+		return f.DeleteDatabaseFunc(ctx, serverID, databaseID)
+	}
+	return nil
+}
+
+// ListBackups calls ListBackupsFunc if it is set.
+func (f *FakeClientClient) ListBackups(ctx context.Context, serverID string) ([]*models.Backup, error) {
+	f.record("ListBackups", serverID)
+	if f.ListBackupsFunc != nil {
+		return f.ListBackupsFunc(ctx, serverID)
+	}
+	return nil, nil
+}
+
+// GetBackup calls GetBackupFunc if it is set.
+func (f *FakeClientClient) GetBackup(ctx context.Context, serverID string, backupUUID string) (*models.Backup, error) {
+	f.record("GetBackup", serverID, backupUUID)
+	if f.GetBackupFunc != nil {
+		return f.GetBackupFunc(ctx, serverID, backupUUID)
+	}
+	return new(models.Backup), nil
+}
+
+// CreateBackup calls CreateBackupFunc if it is set.
+func (f *FakeClientClient) CreateBackup(ctx context.Context, serverID string, req client.CreateBackupRequest) (*models.Backup, error) {
+	f.record("CreateBackup", serverID, req)
+	if f.CreateBackupFunc != nil {
+		return f.CreateBackupFunc(ctx, serverID, req)
+	}
+	return new(models.Backup), nil
+}
+
+// GetBackupDownloadURL calls GetBackupDownloadURLFunc if it is set.
+func (f *FakeClientClient) GetBackupDownloadURL(ctx context.Context, serverID string, backupUUID string) (*models.SignedURL, error) {
+	f.record("GetBackupDownloadURL", serverID, backupUUID)
+	if f.GetBackupDownloadURLFunc != nil {
+		return f.GetBackupDownloadURLFunc(ctx, serverID, backupUUID)
+	}
+	return new(models.SignedURL), nil
+}
+
+// RestoreBackup calls RestoreBackupFunc if it is set.
+func (f *FakeClientClient) RestoreBackup(ctx context.Context, serverID string, backupUUID string, truncate bool) error {
+	f.record("RestoreBackup", serverID, backupUUID, truncate)
+	if f.RestoreBackupFunc != nil {
+		return f.RestoreBackupFunc(ctx, serverID, backupUUID, truncate)
+	}
+	return nil
+}
+
+// ToggleBackupLock calls ToggleBackupLockFunc if it is set.
+func (f *FakeClientClient) ToggleBackupLock(ctx context.Context, serverID string, backupUUID string) error {
+	f.record("ToggleBackupLock", serverID, backupUUID)
+	if f.ToggleBackupLockFunc != nil {
+		return f.ToggleBackupLockFunc(ctx, serverID, backupUUID)
+	}
+	return nil
+}
+
+// DeleteBackup calls DeleteBackupFunc if it is set.
+func (f *FakeClientClient) DeleteBackup(ctx context.Context, serverID string, backupUUID string) error {
+	f.record("DeleteBackup", serverID, backupUUID)
+	if f.DeleteBackupFunc != nil {
+		return f.DeleteBackupFunc(ctx, serverID, backupUUID)
+	}
+	return nil
+}
+
+// GetStartupConfig calls GetStartupConfigFunc if it is set.
+func (f *FakeClientClient) GetStartupConfig(ctx context.Context, serverID string) (*models.StartupConfiguration, error) {
+	f.record("GetStartupConfig", serverID)
+	if f.GetStartupConfigFunc != nil {
+		return f.GetStartupConfigFunc(ctx, serverID)
+	}
+	return new(models.StartupConfiguration), nil
+}
+
+// UpdateStartupVariable calls UpdateStartupVariableFunc if it is set.
+func (f *FakeClientClient) UpdateStartupVariable(ctx context.Context, serverID string, key string, value string) (*models.StartupVariable, error) {
+	f.record("UpdateStartupVariable", serverID, key, value)
+	if f.UpdateStartupVariableFunc != nil {
+		return f.UpdateStartupVariableFunc(ctx, serverID, key, value)
+	}
+	return new(models.StartupVariable), nil
+}
+
+// RenameServer calls RenameServerFunc if it is set.
+func (f *FakeClientClient) RenameServer(ctx context.Context, serverID string, name string, description string) error {
+	f.record("RenameServer", serverID, name, description)
+	if f.RenameServerFunc != nil {
+		return f.RenameServerFunc(ctx, serverID, name, description)
+	}
+	return nil
+}
+
+// ReinstallServer calls ReinstallServerFunc if it is set.
+func (f *FakeClientClient) ReinstallServer(ctx context.Context, serverID string) error {
+	f.record("ReinstallServer", serverID)
+	if f.ReinstallServerFunc != nil {
+		return f.ReinstallServerFunc(ctx, serverID)
+	}
+	return nil
+}
+
+// UpdateDockerImage calls UpdateDockerImageFunc if it is set.
+func (f *FakeClientClient) UpdateDockerImage(ctx context.Context, serverID string, dockerImage string) error {
+	f.record("UpdateDockerImage", serverID, dockerImage)
+	if f.UpdateDockerImageFunc != nil {
+		return f.UpdateDockerImageFunc(ctx, serverID, dockerImage)
+	}
+	return nil
+}
+
+// ListAllocations calls ListAllocationsFunc if it is set.
+func (f *FakeClientClient) ListAllocations(ctx context.Context, serverID string) ([]*models.Allocation, error) {
+	f.record("ListAllocations", serverID)
+	if f.ListAllocationsFunc != nil {
+		return f.ListAllocationsFunc(ctx, serverID)
+	}
+	return nil, nil
+}
+
+// AssignAllocation calls AssignAllocationFunc if it is set.
+func (f *FakeClientClient) AssignAllocation(ctx context.Context, serverID string) error {
+	f.record("AssignAllocation", serverID)
+	if f.AssignAllocationFunc != nil {
+		return f.AssignAllocationFunc(ctx, serverID)
+	}
+	return nil
+}
+
+// SetPrimaryAllocation calls SetPrimaryAllocationFunc if it is set.
+func (f *FakeClientClient) SetPrimaryAllocation(ctx context.Context, serverID string, allocationID int) error {
+	f.record("SetPrimaryAllocation", serverID, allocationID)
+	if f.SetPrimaryAllocationFunc != nil {
+		return f.SetPrimaryAllocationFunc(ctx, serverID, allocationID)
+	}
+	return nil
+}
+
+// UpdateAllocationNotes calls UpdateAllocationNotesFunc if it is set.
+func (f *FakeClientClient) UpdateAllocationNotes(ctx context.Context, serverID string, allocationID int, notes string) error {
+	f.record("UpdateAllocationNotes", serverID, allocationID, notes)
+	if f.UpdateAllocationNotesFunc != nil {
+		return f.UpdateAllocationNotesFunc(ctx, serverID, allocationID, notes)
+	}
+	return nil
+}
+
+// DeleteAllocation calls DeleteAllocationFunc if it is set.
+func (f *FakeClientClient) DeleteAllocation(ctx context.Context, serverID string, allocationID int) error {
+	f.record("DeleteAllocation", serverID, allocationID)
+	if f.DeleteAllocationFunc != nil {
+		return f.DeleteAllocationFunc(ctx, serverID, allocationID)
+	}
+	return nil
+}
+
+// ListSubusers calls ListSubusersFunc if it is set.
+func (f *FakeClientClient) ListSubusers(ctx context.Context, serverID string) ([]*models.Subuser, error) {
+	f.record("ListSubusers", serverID)
+	if f.ListSubusersFunc != nil {
+		return f.ListSubusersFunc(ctx, serverID)
+	}
+	return nil, nil
+}
+
+// GetSubuser calls GetSubuserFunc if it is set.
+func (f *FakeClientClient) GetSubuser(ctx context.Context, serverID string, userUUID string) (*models.Subuser, error) {
+	f.record("GetSubuser", serverID, userUUID)
+	if f.GetSubuserFunc != nil {
+		return f.GetSubuserFunc(ctx, serverID, userUUID)
+	}
+	return new(models.Subuser), nil
+}
+
+// CreateSubuser calls CreateSubuserFunc if it is set.
+func (f *FakeClientClient) CreateSubuser(ctx context.Context, serverID string, email string, permissions []string) (*models.Subuser, error) {
+	f.record("CreateSubuser", serverID, email, permissions)
+	if f.CreateSubuserFunc != nil {
+		return f.CreateSubuserFunc(ctx, serverID, email, permissions)
+	}
+	return new(models.Subuser), nil
+}
+
+// UpdateSubuser calls UpdateSubuserFunc if it is set.
+func (f *FakeClientClient) UpdateSubuser(ctx context.Context, serverID string, userUUID string, permissions []string) (*models.Subuser, error) {
+	f.record("UpdateSubuser", serverID, userUUID, permissions)
+	if f.UpdateSubuserFunc != nil {
+		return f.UpdateSubuserFunc(ctx, serverID, userUUID, permissions)
+	}
+	return new(models.Subuser), nil
+}
+
+// DeleteSubuser calls DeleteSubuserFunc if it is set.
+func (f *FakeClientClient) DeleteSubuser(ctx context.Context, serverID string, userUUID string) error {
+	f.record("DeleteSubuser", serverID, userUUID)
+	if f.DeleteSubuserFunc != nil {
+		return f.DeleteSubuserFunc(ctx, serverID, userUUID)
+	}
+	return nil
+}
+
+// ListSchedules calls ListSchedulesFunc if it is set.
+func (f *FakeClientClient) ListSchedules(ctx context.Context, serverID string) ([]*models.Schedule, error) {
+	f.record("ListSchedules", serverID)
+	if f.ListSchedulesFunc != nil {
+		return f.ListSchedulesFunc(ctx, serverID)
+	}
+	return nil, nil
+}
+
+// GetSchedule calls GetScheduleFunc if it is set.
+func (f *FakeClientClient) GetSchedule(ctx context.Context, serverID string, scheduleID int) (*models.Schedule, error) {
+	f.record("GetSchedule", serverID, scheduleID)
+	if f.GetScheduleFunc != nil {
+		return f.GetScheduleFunc(ctx, serverID, scheduleID)
+	}
+	return new(models.Schedule), nil
+}
+
+// CreateSchedule calls CreateScheduleFunc if it is set.
+func (f *FakeClientClient) CreateSchedule(ctx context.Context, serverID string, req client.CreateScheduleRequest) (*models.Schedule, error) {
+	f.record("CreateSchedule", serverID, req)
+	if f.CreateScheduleFunc != nil {
+		return f.CreateScheduleFunc(ctx, serverID, req)
+	}
+	return new(models.Schedule), nil
+}
+
+// UpdateSchedule calls UpdateScheduleFunc if it is set.
+func (f *FakeClientClient) UpdateSchedule(ctx context.Context, serverID string, scheduleID int, req client.UpdateScheduleRequest) (*models.Schedule, error) {
+	f.record("UpdateSchedule", serverID, scheduleID, req)
+	if f.UpdateScheduleFunc != nil {
+		return f.UpdateScheduleFunc(ctx, serverID, scheduleID, req)
+	}
+	return new(models.Schedule), nil
+}
+
+// DeleteSchedule calls DeleteScheduleFunc if it is set.
+func (f *FakeClientClient) DeleteSchedule(ctx context.Context, serverID string, scheduleID int) error {
+	f.record("DeleteSchedule", serverID, scheduleID)
+	if f.DeleteScheduleFunc != nil {
+		return f.DeleteScheduleFunc(ctx, serverID, scheduleID)
+	}
+	return nil
+}
+
+// ExecuteSchedule calls ExecuteScheduleFunc if it is set.
+func (f *FakeClientClient) ExecuteSchedule(ctx context.Context, serverID string, scheduleID int) error {
+	f.record("ExecuteSchedule", serverID, scheduleID)
+	if f.ExecuteScheduleFunc != nil {
+		return f.ExecuteScheduleFunc(ctx, serverID, scheduleID)
+	}
+	return nil
+}
+
+// CreateScheduleTask calls CreateScheduleTaskFunc if it is set.
+func (f *FakeClientClient) CreateScheduleTask(ctx context.Context, serverID string, scheduleID int, req client.CreateScheduleTaskRequest) (*models.ScheduleTask, error) {
+	f.record("CreateScheduleTask", serverID, scheduleID, req)
+	if f.CreateScheduleTaskFunc != nil {
+		return f.CreateScheduleTaskFunc(ctx, serverID, scheduleID, req)
+	}
+	return new(models.ScheduleTask), nil
+}
+
+// UpdateScheduleTask calls UpdateScheduleTaskFunc if it is set.
+func (f *FakeClientClient) UpdateScheduleTask(ctx context.Context, serverID string, scheduleID int, taskID int, req client.UpdateScheduleTaskRequest) (*models.ScheduleTask, error) {
+	f.record("UpdateScheduleTask", serverID, scheduleID, taskID, req)
+	if f.UpdateScheduleTaskFunc != nil {
+		return f.UpdateScheduleTaskFunc(ctx, serverID, scheduleID, taskID, req)
+	}
+	return new(models.ScheduleTask), nil
+}
+
+// DeleteScheduleTask calls DeleteScheduleTaskFunc if it is set.
+func (f *FakeClientClient) DeleteScheduleTask(ctx context.Context, serverID string, scheduleID int, taskID int) error {
+	f.record("DeleteScheduleTask", serverID, scheduleID, taskID)
+	if f.DeleteScheduleTaskFunc != nil {
+		return f.DeleteScheduleTaskFunc(ctx, serverID, scheduleID, taskID)
+	}
+	return nil
+}
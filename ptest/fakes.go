@@ -0,0 +1,59 @@
+package ptest
+
+import (
+	"errors"
+	"sync"
+)
+
+//go:generate go run ./internal/genfake
+
+// ErrNotConfigured is returned by fake methods whose result has no usable zero
+// value, such as ConnectWebSocket, when no override is set.
+var ErrNotConfigured = errors.New("ptest: fake method not configured")
+
+// Call is a method call recorded by a fake.
+type Call struct {
+	// Method is the name of the method.
+	Method string
+	// Args are the arguments of the call, excluding the context.
+	Args []interface{}
+}
+
+// recorder records calls made to a fake. It is safe for concurrent use.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns all recorded calls in order.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the recorded calls to a method in order.
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Reset clears the recorded calls.
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
@@ -0,0 +1,88 @@
+package ptest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/idanyas/go-pterodactyl/application"
+	"github.com/idanyas/go-pterodactyl/client"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/pagination"
+)
+
+func TestFakeClientClient_Defaults(t *testing.T) {
+	var c client.ClientClient = &FakeClientClient{}
+	ctx := context.Background()
+
+	server, err := c.GetServer(ctx, "abc")
+	if err != nil || server == nil {
+		t.Fatalf("GetServer = %v, %v, want zero server and nil error", server, err)
+	}
+
+	servers, p, err := c.ListServers(ctx, pagination.ListOptions{})
+	if err != nil || servers != nil || p == nil || p.HasMorePages() {
+		t.Fatalf("ListServers = %v, %v, %v, want empty result", servers, p, err)
+	}
+
+	if _, err := c.ConnectWebSocket(ctx, "abc"); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("ConnectWebSocket error = %v, want ErrNotConfigured", err)
+	}
+}
+
+func TestFakeClientClient_Override(t *testing.T) {
+	want := errors.New("offline")
+	fake := &FakeClientClient{
+		SendCommandFunc: func(ctx context.Context, serverID, command string) error {
+			if command == "stop" {
+				return want
+			}
+			return nil
+		},
+	}
+	ctx := context.Background()
+
+	if err := fake.SendCommand(ctx, "abc", "say hi"); err != nil {
+		t.Fatalf("SendCommand: %v", err)
+	}
+	if err := fake.SendCommand(ctx, "abc", "stop"); !errors.Is(err, want) {
+		t.Fatalf("SendCommand error = %v, want %v", err, want)
+	}
+	fake.SendPowerAction(ctx, "abc", "start")
+
+	calls := fake.CallsTo("SendCommand")
+	if len(calls) != 2 {
+		t.Fatalf("got %d SendCommand calls, want 2", len(calls))
+	}
+	if calls[1].Args[0] != "abc" || calls[1].Args[1] != "stop" {
+		t.Errorf("second call args = %v, want [abc stop]", calls[1].Args)
+	}
+	if n := len(fake.Calls()); n != 3 {
+		t.Errorf("got %d calls, want 3", n)
+	}
+
+	fake.Reset()
+	if n := len(fake.Calls()); n != 0 {
+		t.Errorf("got %d calls after Reset, want 0", n)
+	}
+}
+
+func TestFakeApplicationClient_Override(t *testing.T) {
+	fake := &FakeApplicationClient{
+		GetUserFunc: func(ctx context.Context, id int) (*models.User, error) {
+			return &models.User{ID: id, Username: "admin"}, nil
+		},
+	}
+	var a application.ApplicationClient = fake
+
+	user, err := a.GetUser(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.ID != 3 || user.Username != "admin" {
+		t.Errorf("GetUser = %+v, want user 3 admin", user)
+	}
+	if calls := fake.CallsTo("GetUser"); len(calls) != 1 || calls[0].Args[0] != 3 {
+		t.Errorf("GetUser calls = %v, want one call with ID 3", calls)
+	}
+}
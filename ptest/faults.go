@@ -0,0 +1,95 @@
+package ptest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault describes an error response injected by the panel in place of the
+// normal handler. Faults are matched in the order they were injected.
+type Fault struct {
+	// Method restricts the fault to an HTTP method. Empty matches any method.
+	Method string
+	// Path is a prefix of the request path relative to /api/, e.g.
+	// "client/servers/abc". Empty matches any path.
+	Path string
+	// Status is the response status code, e.g. 503 or 429.
+	Status int
+	// Times is how many requests the fault applies to. Zero means every request.
+	Times int
+	// RateLimitReset sets X-RateLimit-Reset on 429 responses. Defaults to one
+	// second from the time of the request.
+	RateLimitReset time.Time
+	// RetryAfter sets the Retry-After header when positive.
+	RetryAfter time.Duration
+	// Delay is how long to wait before responding.
+	Delay time.Duration
+
+	used int
+}
+
+// InjectFault adds a fault. The same Fault value must not be injected twice.
+func (p *Panel) InjectFault(f *Fault) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.faults = append(p.faults, f)
+}
+
+// ClearFaults removes all injected faults.
+func (p *Panel) ClearFaults() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.faults = nil
+}
+
+// matchFaultLocked returns the first fault matching a request and consumes one
+// use of it. p.mu must be held.
+func (p *Panel) matchFaultLocked(method, relPath string) *Fault {
+	for i, f := range p.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, method) {
+			continue
+		}
+		if !strings.HasPrefix(relPath, f.Path) {
+			continue
+		}
+		f.used++
+		if f.Times > 0 && f.used >= f.Times {
+			p.faults = append(p.faults[:i:i], p.faults[i+1:]...)
+		}
+		return f
+	}
+	return nil
+}
+
+// apply writes the fault response.
+func (f *Fault) apply(w http.ResponseWriter, r *http.Request) {
+	if f.Delay > 0 {
+		select {
+		case <-time.After(f.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if f.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((f.RetryAfter+time.Second-1)/time.Second)))
+	}
+
+	status := f.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	if status == http.StatusTooManyRequests {
+		reset := f.RateLimitReset
+		if reset.IsZero() {
+			reset = time.Now().Add(time.Second)
+		}
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		writeError(w, status, "TooManyRequestsHttpException", "Too Many Attempts.")
+		return
+	}
+	writeError(w, status, "HttpException", http.StatusText(status))
+}
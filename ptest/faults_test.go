@@ -0,0 +1,76 @@
+package ptest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl"
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+func TestFault_ClientError(t *testing.T) {
+	p, c := newTestPanel(t)
+	p.InjectFault(&Fault{Method: http.MethodGet, Path: "application/nodes", Status: http.StatusForbidden})
+
+	_, err := c.Application().GetNode(context.Background(), 2)
+	var apiErr *pterodactyl.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("GetNode() error = %v, want a 403 APIError", err)
+	}
+
+	// Other paths are unaffected.
+	if _, err := c.Application().GetLocation(context.Background(), 1); err != nil {
+		t.Errorf("GetLocation() error = %v", err)
+	}
+
+	p.ClearFaults()
+	if _, err := c.Application().GetNode(context.Background(), 2); err != nil {
+		t.Errorf("GetNode() after ClearFaults error = %v", err)
+	}
+}
+
+func TestFault_ServerErrorRetried(t *testing.T) {
+	p, c := newTestPanel(t)
+	p.InjectFault(&Fault{Status: http.StatusServiceUnavailable, Times: 1})
+
+	if _, err := c.Application().GetNode(context.Background(), 2); err != nil {
+		t.Fatalf("GetNode() error = %v, want success after a retry", err)
+	}
+	if got := len(p.Requests()); got != 2 {
+		t.Errorf("Requests() = %d, want 2", got)
+	}
+}
+
+func TestFault_RateLimited(t *testing.T) {
+	p, c := newTestPanel(t)
+	server := p.AddServer(models.Server{Name: "Lobby"})
+	p.InjectFault(&Fault{
+		Path:   "client/servers/" + server.Identifier,
+		Status: http.StatusTooManyRequests,
+		Times:  1,
+		// X-RateLimit-Reset has a resolution of one second.
+		RateLimitReset: time.Now().Add(2 * time.Second),
+	})
+
+	start := time.Now()
+	if _, err := c.Client().GetServer(context.Background(), server.Identifier); err != nil {
+		t.Fatalf("GetServer() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("GetServer() returned after %v, want it to wait for the reset", elapsed)
+	}
+}
+
+func TestFault_Delay(t *testing.T) {
+	p, c := newTestPanel(t)
+	p.InjectFault(&Fault{Status: http.StatusBadRequest, Delay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Application().GetNode(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetNode() error = %v, want context.DeadlineExceeded", err)
+	}
+}
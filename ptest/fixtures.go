@@ -0,0 +1,346 @@
+package ptest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+// Fixtures is a set of resources to seed a panel with.
+type Fixtures struct {
+	Locations []models.Location
+	Nodes     []models.Node
+	// Allocations are keyed by node ID. Allocation.ServerID assigns an
+	// allocation to a server.
+	Allocations map[int][]models.Allocation
+	Users       []models.User
+	Servers     []models.Server
+	Nests       []models.Nest
+	Eggs        []models.Egg
+}
+
+// Seed adds all fixtures to the panel in dependency order. Resources with a
+// zero ID are assigned one; explicit IDs are kept, so fixtures can reference
+// each other.
+func (p *Panel) Seed(f Fixtures) {
+	for _, v := range f.Locations {
+		p.AddLocation(v)
+	}
+	for _, v := range f.Nodes {
+		p.AddNode(v)
+	}
+	for nodeID, allocations := range f.Allocations {
+		for _, v := range allocations {
+			p.AddAllocation(nodeID, v)
+		}
+	}
+	for _, v := range f.Users {
+		p.AddUser(v)
+	}
+	for _, v := range f.Nests {
+		p.AddNest(v)
+	}
+	for _, v := range f.Eggs {
+		p.AddEgg(v)
+	}
+	for _, v := range f.Servers {
+		p.AddServer(v)
+	}
+}
+
+// id returns id if it is set and reserves it, or a new ID otherwise. p.mu must be held.
+func (p *Panel) idLocked(id int) int {
+	if id <= 0 {
+		return p.newID()
+	}
+	if id > p.nextID {
+		p.nextID = id
+	}
+	return id
+}
+
+// AddLocation adds a location and returns a copy of the stored value.
+func (p *Panel) AddLocation(l models.Location) *models.Location {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	l.ID = p.idLocked(l.ID)
+	stamp(&l.CreatedAt, &l.UpdatedAt)
+	p.locations[l.ID] = &l
+	out := l
+	return &out
+}
+
+// AddNode adds a node and returns a copy of the stored value.
+func (p *Panel) AddNode(n models.Node) *models.Node {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n.ID = p.idLocked(n.ID)
+	if n.UUID == "" {
+		n.UUID = newUUID()
+	}
+	if n.Name == "" {
+		n.Name = "node-" + n.UUID[:8]
+	}
+	stamp(&n.CreatedAt, &n.UpdatedAt)
+	p.nodes[n.ID] = &n
+	out := n
+	return &out
+}
+
+// AddAllocation adds an allocation to a node and returns a copy of the stored
+// value. Allocation.ServerID assigns it to a server by internal ID.
+func (p *Panel) AddAllocation(nodeID int, a models.Allocation) *models.Allocation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	a.ID = p.idLocked(a.ID)
+	if a.IP == "" {
+		a.IP = "127.0.0.1"
+	}
+	p.allocations[a.ID] = &a
+	p.allocationNodes[a.ID] = nodeID
+	out := a
+	return &out
+}
+
+// AddUser adds a user and returns a copy of the stored value. The first user
+// added becomes the account returned by the Client API.
+func (p *Panel) AddUser(u models.User) *models.User {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	u.ID = p.idLocked(u.ID)
+	if u.UUID == "" {
+		u.UUID = newUUID()
+	}
+	if u.Language == "" {
+		u.Language = "en"
+	}
+	stamp(&u.CreatedAt, &u.UpdatedAt)
+	p.users[u.ID] = &u
+	if p.account == 0 {
+		p.account = u.ID
+	}
+	out := u
+	return &out
+}
+
+// SetAccount sets the user returned by the Client API account endpoints.
+func (p *Panel) SetAccount(userID int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.account = userID
+}
+
+// AddNest adds a nest and returns a copy of the stored value.
+func (p *Panel) AddNest(n models.Nest) *models.Nest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n.ID = p.idLocked(n.ID)
+	if n.UUID == "" {
+		n.UUID = newUUID()
+	}
+	stamp(&n.CreatedAt, &n.UpdatedAt)
+	p.nests[n.ID] = &n
+	out := n
+	return &out
+}
+
+// AddEgg adds an egg to the nest set in Egg.Nest and returns a copy of the stored value.
+func (p *Panel) AddEgg(e models.Egg) *models.Egg {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e.ID = p.idLocked(e.ID)
+	if e.UUID == "" {
+		e.UUID = newUUID()
+	}
+	stamp(&e.CreatedAt, &e.UpdatedAt)
+	p.eggs[e.ID] = &e
+	out := e
+	return &out
+}
+
+// AddServer adds a server in the offline state and returns a copy of the stored
+// value. UUID and Identifier are generated when empty.
+func (p *Panel) AddServer(s models.Server) *models.Server {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.addServerLocked(s)
+}
+
+// addServerLocked adds a server. p.mu must be held.
+func (p *Panel) addServerLocked(s models.Server) *models.Server {
+	s.ID = p.idLocked(s.ID)
+	s.InternalID = s.ID
+	if s.UUID == "" {
+		s.UUID = newUUID()
+	}
+	if s.Identifier == "" {
+		s.Identifier = s.UUID[:8]
+	}
+	if node, ok := p.nodes[s.NodeID]; ok && s.Node == "" {
+		s.Node = node.Name
+	}
+	if a, ok := p.allocations[s.AllocationID]; ok {
+		a.ServerID = s.ID
+		a.IsDefault = true
+	}
+	stamp(&s.CreatedAt, &s.UpdatedAt)
+	p.servers[s.ID] = &s
+	p.runtime[s.Identifier] = &serverState{
+		state:     "offline",
+		files:     make(map[string]string),
+		dirs:      make(map[string]bool),
+		snapshots: make(map[string]map[string]string),
+	}
+	out := s
+	return &out
+}
+
+// Server returns a copy of a server by its identifier, or nil if it does not exist.
+func (p *Panel) Server(identifier string) *models.Server {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s := p.serverLocked(identifier); s != nil {
+		out := *s
+		return &out
+	}
+	return nil
+}
+
+// User returns a copy of a user by ID, or nil if it does not exist.
+func (p *Panel) User(id int) *models.User {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if u, ok := p.users[id]; ok {
+		out := *u
+		return &out
+	}
+	return nil
+}
+
+// serverLocked finds a server by identifier or UUID. p.mu must be held.
+func (p *Panel) serverLocked(identifier string) *models.Server {
+	for _, s := range p.servers {
+		if s.Identifier == identifier || s.UUID == identifier {
+			return s
+		}
+	}
+	return nil
+}
+
+// State returns the current power state of a server.
+func (p *Panel) State(identifier string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if rt, ok := p.runtime[identifier]; ok {
+		return rt.state
+	}
+	return ""
+}
+
+// SetState sets the power state of a server and notifies WebSocket listeners.
+func (p *Panel) SetState(identifier, state string) {
+	p.mu.Lock()
+	rt, ok := p.runtime[identifier]
+	if ok {
+		rt.state = state
+	}
+	p.mu.Unlock()
+	if ok {
+		p.wings.broadcast(identifier, "status", state)
+	}
+}
+
+// SetResources sets the resource usage of a server and sends a stats event to
+// WebSocket listeners.
+func (p *Panel) SetResources(identifier string, r models.Resources) {
+	p.mu.Lock()
+	rt, ok := p.runtime[identifier]
+	var state string
+	if ok {
+		rt.resources = r
+		state = rt.state
+	}
+	p.mu.Unlock()
+	if ok {
+		p.wings.broadcastStats(identifier, state, r)
+	}
+}
+
+// Console sends a console output line to WebSocket listeners of a server.
+func (p *Panel) Console(identifier, line string) {
+	p.wings.broadcast(identifier, "console output", line)
+}
+
+// Commands returns the console commands sent to a server through the REST API
+// or the WebSocket.
+func (p *Panel) Commands(identifier string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if rt, ok := p.runtime[identifier]; ok {
+		return append([]string(nil), rt.commands...)
+	}
+	return nil
+}
+
+// SetFile creates or replaces a file on a server.
+func (p *Panel) SetFile(identifier, filePath, content string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if rt, ok := p.runtime[identifier]; ok {
+		rt.files[cleanPath(filePath)] = content
+	}
+}
+
+// File returns the contents of a file on a server.
+func (p *Panel) File(identifier, filePath string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if rt, ok := p.runtime[identifier]; ok {
+		content, ok := rt.files[cleanPath(filePath)]
+		return content, ok
+	}
+	return "", false
+}
+
+// Backups returns copies of the backups of a server.
+func (p *Panel) Backups(identifier string) []models.Backup {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rt, ok := p.runtime[identifier]
+	if !ok {
+		return nil
+	}
+	out := make([]models.Backup, len(rt.backups))
+	for i, b := range rt.backups {
+		out[i] = *b
+	}
+	return out
+}
+
+// stamp sets zero timestamps to the current time.
+func stamp(created, updated *time.Time) {
+	now := time.Now().UTC().Truncate(time.Second)
+	if created.IsZero() {
+		*created = now
+	}
+	if updated.IsZero() {
+		*updated = *created
+	}
+}
+
+// readBody reads the request body and replaces it so handlers can read it again.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
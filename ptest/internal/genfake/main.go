@@ -0,0 +1,347 @@
+// Command genfake generates the programmable fakes in package ptest from the
+// ApplicationClient and ClientClient interface declarations.
+//
+// It is run by go generate from the ptest directory:
+//
+//	go generate ./ptest
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// target describes a fake to generate.
+type target struct {
+	// Source is the file declaring the interface, relative to the module root.
+	Source string
+	// Package is the name of the package declaring the interface.
+	Package string
+	// Interface is the name of the interface to fake.
+	Interface string
+	// Fake is the name of the generated type.
+	Fake string
+	// Output is the generated file, relative to the ptest directory.
+	Output string
+}
+
+var targets = []target{
+	{"application/application.go", "application", "ApplicationClient", "FakeApplicationClient", "fake_application.go"},
+	{"client/client.go", "client", "ClientClient", "FakeClientClient", "fake_client.go"},
+}
+
+// importPaths maps package qualifiers used in method signatures to import paths.
+var importPaths = map[string]string{
+	"context":     "context",
+	"http":        "net/http",
+	"application": "github.com/idanyas/go-pterodactyl/application",
+	"client":      "github.com/idanyas/go-pterodactyl/client",
+	"models":      "github.com/idanyas/go-pterodactyl/models",
+	"pagination":  "github.com/idanyas/go-pterodactyl/pagination",
+	"websocket":   "github.com/idanyas/go-pterodactyl/websocket",
+}
+
+// unconfigured lists result types that have no usable zero value. Methods
+// returning them report ErrNotConfigured unless overridden.
+var unconfigured = map[string]bool{
+	"*websocket.Conn": true,
+}
+
+func main() {
+	root := flag.String("root", "..", "module root directory")
+	flag.Parse()
+
+	for _, t := range targets {
+		src, err := os.ReadFile(filepath.Join(*root, t.Source))
+		if err != nil {
+			log.Fatal(err)
+		}
+		out, err := generate(src, t)
+		if err != nil {
+			log.Fatalf("%s: %v", t.Source, err)
+		}
+		if err := os.WriteFile(t.Output, out, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// param is a parameter or result of a method.
+type param struct {
+	Name string
+	Type string
+}
+
+// method is an interface method.
+type method struct {
+	Name     string
+	Params   []param
+	Results  []param
+	Variadic bool
+}
+
+// generate returns the source of the fake for a target.
+func generate(src []byte, t target) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, t.Source, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	iface, err := findInterface(file, t.Interface)
+	if err != nil {
+		return nil, err
+	}
+
+	var methods []method
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			return nil, fmt.Errorf("%s: embedded interfaces are not supported", t.Interface)
+		}
+		m := method{Name: field.Names[0].Name}
+		m.Params, m.Variadic = params(fset, fn.Params, t.Package, "arg")
+		if fn.Results != nil {
+			m.Results, _ = params(fset, fn.Results, t.Package, "")
+		}
+		methods = append(methods, m)
+	}
+
+	var body bytes.Buffer
+	imports := map[string]bool{t.Package: true}
+	writeFake(&body, t, methods, imports)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by genfake. DO NOT EDIT.\n\npackage ptest\n\nimport (\n")
+	names := make([]string, 0, len(imports))
+	for name := range imports {
+		if importPaths[name] != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return importPaths[names[i]] < importPaths[names[j]] })
+	stdlib := true
+	for _, name := range names {
+		path := importPaths[name]
+		if stdlib && strings.Contains(path, ".") {
+			stdlib = false
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	buf.WriteString(")\n\n")
+	buf.Write(body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+// findInterface returns the declaration of the named interface.
+func findInterface(file *ast.File, name string) (*ast.InterfaceType, error) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != name {
+				continue
+			}
+			iface, ok := ts.Type.(*ast.InterfaceType)
+			if !ok {
+				return nil, fmt.Errorf("%s is not an interface", name)
+			}
+			return iface, nil
+		}
+	}
+	return nil, fmt.Errorf("interface %s not found", name)
+}
+
+// params flattens a field list, naming unnamed fields with prefix and an index
+// and qualifying types declared in pkg.
+func params(fset *token.FileSet, fields *ast.FieldList, pkg, prefix string) ([]param, bool) {
+	var out []param
+	variadic := false
+	for _, field := range fields.List {
+		expr := field.Type
+		if ell, ok := expr.(*ast.Ellipsis); ok {
+			variadic = true
+			expr = &ast.ArrayType{Elt: ell.Elt}
+		}
+		typ := typeString(fset, qualify(expr, pkg))
+
+		if len(field.Names) == 0 {
+			name := ""
+			if prefix != "" {
+				name = fmt.Sprintf("%s%d", prefix, len(out))
+			}
+			out = append(out, param{Name: name, Type: typ})
+			continue
+		}
+		for _, n := range field.Names {
+			out = append(out, param{Name: n.Name, Type: typ})
+		}
+	}
+	return out, variadic
+}
+
+// qualify rewrites references to exported types declared in pkg as pkg.Name.
+func qualify(expr ast.Expr, pkg string) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if e.IsExported() {
+			return &ast.SelectorExpr{X: ast.NewIdent(pkg), Sel: ast.NewIdent(e.Name)}
+		}
+		return e
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X, pkg)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt, pkg)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key, pkg), Value: qualify(e.Value, pkg)}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: qualify(e.X, pkg), Index: qualify(e.Index, pkg)}
+	case *ast.IndexListExpr:
+		indices := make([]ast.Expr, len(e.Indices))
+		for i, idx := range e.Indices {
+			indices[i] = qualify(idx, pkg)
+		}
+		return &ast.IndexListExpr{X: qualify(e.X, pkg), Indices: indices}
+	}
+	return expr
+}
+
+func typeString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, expr)
+	return buf.String()
+}
+
+// writeFake writes the fake type and its methods, recording the packages used.
+func writeFake(buf *bytes.Buffer, t target, methods []method, imports map[string]bool) {
+	use := func(typ string) {
+		for name := range importPaths {
+			if strings.Contains(typ, name+".") {
+				imports[name] = true
+			}
+		}
+	}
+
+	fmt.Fprintf(buf, "// %s is a programmable fake of %s.%s.\n", t.Fake, t.Package, t.Interface)
+	fmt.Fprintf(buf, "// Each method calls the matching Func field if it is set. Otherwise it returns\n")
+	fmt.Fprintf(buf, "// zero values: pointers to zero structs, empty paginators, nil slices and a nil\n")
+	fmt.Fprintf(buf, "// error. Every call is recorded and can be inspected with Calls and CallsTo.\n")
+	fmt.Fprintf(buf, "type %s struct {\n\trecorder\n\n", t.Fake)
+	for _, m := range methods {
+		sig := signature(m)
+		use(sig)
+		fmt.Fprintf(buf, "\t%sFunc func%s\n", m.Name, sig)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+	fmt.Fprintf(buf, "var _ %s.%s = (*%s)(nil)\n", t.Package, t.Interface, t.Fake)
+
+	for _, m := range methods {
+		fmt.Fprintf(buf, "\n// %s calls %sFunc if it is set.\n", m.Name, m.Name)
+		fmt.Fprintf(buf, "func (f *%s) %s%s {\n", t.Fake, m.Name, signature(m))
+
+		var recorded, args []string
+		for i, p := range m.Params {
+			arg := p.Name
+			if m.Variadic && i == len(m.Params)-1 {
+				arg += "..."
+			}
+			args = append(args, arg)
+			if p.Type != "context.Context" {
+				recorded = append(recorded, p.Name)
+			}
+		}
+		fmt.Fprintf(buf, "\tf.record(%q%s)\n", m.Name, prefixed(recorded))
+		fmt.Fprintf(buf, "\tif f.%sFunc != nil {\n", m.Name)
+		if len(m.Results) > 0 {
+			fmt.Fprintf(buf, "\t\treturn f.%sFunc(%s)\n\t}\n", m.Name, strings.Join(args, ", "))
+		} else {
+			fmt.Fprintf(buf, "\t\tf.%sFunc(%s)\n\t}\n", m.Name, strings.Join(args, ", "))
+		}
+		if len(m.Results) > 0 {
+			fmt.Fprintf(buf, "\treturn %s\n", strings.Join(defaults(m.Results), ", "))
+		}
+		fmt.Fprintf(buf, "}\n")
+	}
+}
+
+// signature formats the parameters and results of a method.
+func signature(m method) string {
+	ps := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		typ := p.Type
+		if m.Variadic && i == len(m.Params)-1 {
+			typ = "..." + strings.TrimPrefix(typ, "[]")
+		}
+		ps[i] = p.Name + " " + typ
+	}
+	rs := make([]string, len(m.Results))
+	for i, r := range m.Results {
+		rs[i] = r.Type
+	}
+
+	sig := "(" + strings.Join(ps, ", ") + ")"
+	switch len(rs) {
+	case 0:
+	case 1:
+		sig += " " + rs[0]
+	default:
+		sig += " (" + strings.Join(rs, ", ") + ")"
+	}
+	return sig
+}
+
+// defaults returns the default values of a method's results.
+func defaults(results []param) []string {
+	missing := false
+	for _, r := range results {
+		if unconfigured[r.Type] {
+			missing = true
+		}
+	}
+
+	out := make([]string, len(results))
+	for i, r := range results {
+		switch {
+		case r.Type == "error" && missing:
+			out[i] = "ErrNotConfigured"
+		case r.Type == "error", unconfigured[r.Type]:
+			out[i] = "nil"
+		case strings.HasPrefix(r.Type, "*"):
+			out[i] = "new(" + r.Type[1:] + ")"
+		case strings.HasPrefix(r.Type, "[]"), strings.HasPrefix(r.Type, "map["), r.Type == "interface{}":
+			out[i] = "nil"
+		case r.Type == "string":
+			out[i] = `""`
+		case r.Type == "bool":
+			out[i] = "false"
+		case r.Type == "int", r.Type == "int64", r.Type == "float64":
+			out[i] = "0"
+		default:
+			out[i] = "*new(" + r.Type + ")"
+		}
+	}
+	return out
+}
+
+func prefixed(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(args, ", ")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	for _, tgt := range targets {
+		src, err := os.ReadFile(filepath.Join("..", "..", "..", tgt.Source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := generate(src, tgt)
+		if err != nil {
+			t.Fatalf("%s: %v", tgt.Source, err)
+		}
+		got, err := os.ReadFile(filepath.Join("..", "..", tgt.Output))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date; run go generate ./ptest", tgt.Output)
+		}
+	}
+}
+
+func TestGenerate_Defaults(t *testing.T) {
+	src := []byte(`package example
+
+type Widget struct{}
+
+type Service interface {
+	Get(ctx context.Context, id int) (*Widget, error)
+	Names(ctx context.Context, ids ...int) ([]string, bool, error)
+	Conn(ctx context.Context) (*websocket.Conn, error)
+	Close()
+}
+`)
+	out, err := generate(src, target{
+		Source:    "example.go",
+		Package:   "example",
+		Interface: "Service",
+		Fake:      "FakeService",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"return new(example.Widget), nil",
+		"return nil, false, nil",
+		"return f.NamesFunc(ctx, ids...)",
+		`f.record("Names", ids)`,
+		"return nil, ErrNotConfigured",
+		"var _ example.Service = (*FakeService)(nil)",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("generated source does not contain %q:\n%s", want, out)
+		}
+	}
+}
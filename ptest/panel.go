@@ -0,0 +1,420 @@
+// Package ptest provides an in-memory fake Pterodactyl panel for tests.
+//
+// A Panel serves the Application and Client API endpoints used by this library
+// from seedable, stateful fixtures, supports fault injection, and includes a fake
+// Wings WebSocket so power actions and console commands can be observed:
+//
+//	panel := ptest.NewPanel()
+//	defer panel.Close()
+//
+//	server := panel.AddServer(models.Server{Name: "Lobby"})
+//	c, _ := panel.Client()
+//	err := c.Client().SendPowerAction(ctx, server.Identifier, "start")
+//	// panel.State(server.Identifier) == "running"
+package ptest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl"
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+// DefaultAPIKey is the Client API key used by Panel.Client.
+const DefaultAPIKey = "ptlc_ptestdefaultkey0000000000000000000000000000"
+
+// DefaultApplicationKey is the Application API key used by Panel.Client.
+const DefaultApplicationKey = "ptla_ptestdefaultkey0000000000000000000000000000"
+
+const defaultRateLimit = 240
+
+// RecordedRequest is a request received by the fake panel.
+type RecordedRequest struct {
+	Method string
+	// Path is the request path relative to /api/, e.g. "client/servers/abc/power".
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// serverState is the runtime state of a server that is not part of models.Server.
+type serverState struct {
+	state     string
+	resources models.Resources
+	files     map[string]string
+	dirs      map[string]bool
+	backups   []*models.Backup
+	// snapshots holds the files captured by each backup, keyed by backup UUID.
+	snapshots map[string]map[string]string
+	schedules []*models.Schedule
+	databases []*models.Database
+	commands  []string
+}
+
+// apiKey is an API key registered with the panel.
+type apiKey struct {
+	token string
+	key   models.APIKey
+}
+
+// Panel is an in-memory fake Pterodactyl panel backed by an httptest.Server.
+// It is safe for concurrent use.
+type Panel struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	nextID      int
+	users       map[int]*models.User
+	servers     map[int]*models.Server
+	nodes       map[int]*models.Node
+	locations   map[int]*models.Location
+	allocations map[int]*models.Allocation
+	// allocationNodes maps allocation IDs to node IDs.
+	allocationNodes map[int]int
+	nests           map[int]*models.Nest
+	eggs            map[int]*models.Egg
+	runtime         map[string]*serverState
+	account         int
+	apiKeys         map[string]*apiKey
+	requireAuth     bool
+	faults          []*Fault
+	requests        []RecordedRequest
+	rateLimit       int
+	windowStart     time.Time
+	windowCount     int
+	powerHandler    func(identifier, signal string) []string
+	// downloads maps signed download tokens to file contents.
+	downloads map[string]string
+	wings     *wings
+}
+
+// NewPanel starts a new fake panel. Call Close when done.
+func NewPanel() *Panel {
+	p := &Panel{
+		users:           make(map[int]*models.User),
+		servers:         make(map[int]*models.Server),
+		nodes:           make(map[int]*models.Node),
+		locations:       make(map[int]*models.Location),
+		allocations:     make(map[int]*models.Allocation),
+		allocationNodes: make(map[int]int),
+		nests:           make(map[int]*models.Nest),
+		eggs:            make(map[int]*models.Egg),
+		runtime:         make(map[string]*serverState),
+		apiKeys:         make(map[string]*apiKey),
+		downloads:       make(map[string]string),
+		rateLimit:       defaultRateLimit,
+	}
+	p.wings = newWings(p)
+
+	mux := http.NewServeMux()
+	p.registerApplication(mux)
+	p.registerClient(mux)
+	p.wings.register(mux)
+
+	p.server = httptest.NewServer(p.middleware(mux))
+	return p
+}
+
+// URL returns the base URL of the panel, suitable for pterodactyl.New.
+func (p *Panel) URL() string {
+	return p.server.URL
+}
+
+// Close shuts down the panel and all WebSocket connections.
+func (p *Panel) Close() {
+	p.wings.closeAll()
+	p.server.Close()
+}
+
+// Client creates a pterodactyl.Client configured for the panel. Options are
+// applied after the panel's URL, DefaultAPIKey and DefaultApplicationKey.
+func (p *Panel) Client(opts ...pterodactyl.Option) (*pterodactyl.Client, error) {
+	opts = append([]pterodactyl.Option{
+		pterodactyl.WithAPIKey(DefaultAPIKey),
+		pterodactyl.WithApplicationKey(DefaultApplicationKey),
+	}, opts...)
+	return pterodactyl.New(p.URL(), opts...)
+}
+
+// Requests returns all requests received so far, excluding WebSocket traffic.
+func (p *Panel) Requests() []RecordedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]RecordedRequest(nil), p.requests...)
+}
+
+// SetRateLimit sets the value of the X-RateLimit-Limit header. Remaining is
+// derived from the number of requests in the current minute. The limit is
+// reported, not enforced; use a Fault to simulate 429 responses.
+func (p *Panel) SetRateLimit(limit int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rateLimit = limit
+}
+
+// RequireAPIKeys registers API keys and rejects requests that do not use one of
+// the registered keys. Keys created through the Client API are accepted as well.
+func (p *Panel) RequireAPIKeys(tokens ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requireAuth = true
+	for _, token := range tokens {
+		p.registerKeyLocked(token, "")
+	}
+}
+
+// registerKeyLocked registers a key and returns it. p.mu must be held.
+func (p *Panel) registerKeyLocked(token, description string) *apiKey {
+	identifier := token
+	if len(identifier) > 16 {
+		identifier = identifier[:16]
+	}
+	k := &apiKey{
+		token: token,
+		key: models.APIKey{
+			Identifier:  identifier,
+			Description: description,
+			AllowedIPs:  []string{},
+			CreatedAt:   time.Now().UTC(),
+		},
+	}
+	p.apiKeys[identifier] = k
+	return k
+}
+
+// SetPowerHandler overrides the states a server goes through for a power signal.
+// The default handler moves through starting/running for start, stopping/offline
+// for stop, both for restart, and directly to offline for kill.
+func (p *Panel) SetPowerHandler(fn func(identifier, signal string) []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.powerHandler = fn
+}
+
+// newID returns a new unique numeric ID. p.mu must be held.
+func (p *Panel) newID() int {
+	p.nextID++
+	return p.nextID
+}
+
+// newUUID returns a random UUID.
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// randomHex returns n random bytes encoded as hex.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// middleware records requests, applies authentication, faults and rate-limit headers.
+func (p *Panel) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := readBody(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "BadRequestHttpException", err.Error())
+			return
+		}
+		relPath := strings.TrimPrefix(r.URL.Path, "/api/")
+
+		p.mu.Lock()
+		p.requests = append(p.requests, RecordedRequest{
+			Method: r.Method,
+			Path:   relPath,
+			Query:  r.URL.RawQuery,
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		now := time.Now()
+		if now.Sub(p.windowStart) >= time.Minute {
+			p.windowStart = now
+			p.windowCount = 0
+		}
+		p.windowCount++
+		remaining := p.rateLimit - p.windowCount
+		if remaining < 0 {
+			remaining = 0
+		}
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(p.rateLimit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		authorized := p.authorizedLocked(r.Header.Get("Authorization"))
+		fault := p.matchFaultLocked(r.Method, relPath)
+		p.mu.Unlock()
+
+		if !authorized {
+			writeError(w, http.StatusUnauthorized, "AuthenticationException", "Unauthenticated.")
+			return
+		}
+		if fault != nil {
+			fault.apply(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorizedLocked reports whether the Authorization header is acceptable. p.mu must be held.
+func (p *Panel) authorizedLocked(header string) bool {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	if !p.requireAuth {
+		return true
+	}
+	for _, k := range p.apiKeys {
+		if k.token == token {
+			now := time.Now().UTC()
+			k.key.LastUsedAt = &now
+			return true
+		}
+	}
+	return false
+}
+
+// errorDetail mirrors the error objects returned by the panel.
+type errorDetail struct {
+	Code   string            `json:"code"`
+	Status string            `json:"status"`
+	Detail string            `json:"detail"`
+	Source map[string]string `json:"source,omitempty"`
+}
+
+// writeError writes an error response in the panel's format.
+func writeError(w http.ResponseWriter, status int, code, detail string) {
+	writeJSON(w, status, map[string]interface{}{
+		"errors": []errorDetail{{Code: code, Status: strconv.Itoa(status), Detail: detail}},
+	})
+}
+
+// writeValidationError writes a 422 response for a single invalid field.
+func writeValidationError(w http.ResponseWriter, field, detail string) {
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+		"errors": []errorDetail{{
+			Code:   "ValidationException",
+			Status: "422",
+			Detail: detail,
+			Source: map[string]string{"field": field},
+		}},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "NotFoundHttpException", "The requested resource could not be found on the server.")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// item wraps a resource in the panel's object envelope.
+func item(object string, attributes interface{}) map[string]interface{} {
+	return map[string]interface{}{"object": object, "attributes": attributes}
+}
+
+// list wraps resources in the panel's list envelope without pagination.
+func list[T any](object string, items []T) map[string]interface{} {
+	data := make([]interface{}, len(items))
+	for i, v := range items {
+		data[i] = item(object, v)
+	}
+	return map[string]interface{}{"object": "list", "data": data}
+}
+
+// paginated wraps a page of resources in the panel's list envelope, honouring
+// the page and per_page query parameters.
+func paginated[T any](r *http.Request, object string, items []T) map[string]interface{} {
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage <= 0 {
+		perPage = 50
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+	totalPages := (len(items) + perPage - 1) / perPage
+
+	resp := list(object, items[start:end])
+	resp["meta"] = map[string]interface{}{
+		"pagination": models.Pagination{
+			Total:       len(items),
+			Count:       end - start,
+			PerPage:     perPage,
+			CurrentPage: page,
+			TotalPages:  totalPages,
+		},
+	}
+	return resp
+}
+
+// sortedValues returns the values of a map ordered by key.
+func sortedValues[T any](m map[int]*T) []*T {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	out := make([]*T, len(keys))
+	for i, k := range keys {
+		out[i] = m[k]
+	}
+	return out
+}
+
+// pathID parses a numeric path value.
+func pathID(r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	return id, err == nil && id > 0
+}
+
+// decode decodes a JSON request body into v, writing a 400 response on failure.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequestHttpException", fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// cleanPath normalises a file path to an absolute, slash-separated form.
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
@@ -0,0 +1,430 @@
+package ptest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/idanyas/go-pterodactyl"
+	"github.com/idanyas/go-pterodactyl/application"
+	"github.com/idanyas/go-pterodactyl/client"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/pagination"
+)
+
+// newTestPanel starts a panel seeded with a location, node, allocations, user,
+// nest and egg, and returns it with a connected client.
+func newTestPanel(t *testing.T) (*Panel, *pterodactyl.Client) {
+	t.Helper()
+	p := NewPanel()
+	t.Cleanup(p.Close)
+
+	p.Seed(Fixtures{
+		Locations: []models.Location{{ID: 1, Short: "eu"}},
+		Nodes:     []models.Node{{ID: 2, Name: "node-1", LocationID: 1, Public: true, Memory: 4096, Disk: 10240}},
+		Allocations: map[int][]models.Allocation{
+			2: {{ID: 10, Port: 25565}, {ID: 11, Port: 25566}},
+		},
+		Users: []models.User{{ID: 3, Username: "admin", Email: "admin@example.com", RootAdmin: true}},
+		Nests: []models.Nest{{ID: 4, Name: "Minecraft"}},
+		Eggs:  []models.Egg{{ID: 5, Nest: 4, Name: "Paper", DockerImage: "java:17", Startup: "java -jar server.jar"}},
+	})
+
+	c, err := p.Client()
+	if err != nil {
+		t.Fatalf("Client() error = %v", err)
+	}
+	return p, c
+}
+
+func TestPanel_CreateServer(t *testing.T) {
+	p, c := newTestPanel(t)
+	ctx := context.Background()
+
+	req := application.CreateServerRequest{
+		Name:          "Lobby",
+		User:          3,
+		Egg:           5,
+		Limits:        models.Limits{Memory: 1024, Disk: 2048},
+		FeatureLimits: models.FeatureLimits{Backups: 1},
+		Allocation:    application.CreateServerAllocation{Default: 10},
+		ExternalID:    "lobby",
+	}
+	server, err := c.Application().CreateServer(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateServer() error = %v", err)
+	}
+	if server.NodeID != 2 || server.NestID != 4 || server.DockerImage != "java:17" {
+		t.Errorf("server = %+v, want node 2, nest 4 and the egg's image", server)
+	}
+	if p.State(server.Identifier) != "offline" {
+		t.Errorf("State() = %q, want offline", p.State(server.Identifier))
+	}
+
+	got, err := c.Application().GetServerExternal(ctx, "lobby")
+	if err != nil {
+		t.Fatalf("GetServerExternal() error = %v", err)
+	}
+	if got.ID != server.ID {
+		t.Errorf("GetServerExternal() ID = %d, want %d", got.ID, server.ID)
+	}
+
+	// The external ID and the allocation are now taken.
+	req.Allocation.Default = 11
+	_, err = c.Application().CreateServer(ctx, req)
+	var apiErr *pterodactyl.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("CreateServer() error = %v, want a 422 APIError", err)
+	}
+	if apiErr.Errors[0].Source == nil || apiErr.Errors[0].Source.Field != "external_id" {
+		t.Errorf("error source = %+v, want external_id", apiErr.Errors[0].Source)
+	}
+}
+
+func TestPanel_Users(t *testing.T) {
+	_, c := newTestPanel(t)
+	ctx := context.Background()
+
+	user, err := c.Application().CreateUser(ctx, application.CreateUserRequest{
+		Email: "jane@example.com", Username: "jane", FirstName: "Jane", LastName: "Doe",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	_, err = c.Application().CreateUser(ctx, application.CreateUserRequest{
+		Email: "jane@example.com", Username: "jane2", FirstName: "Jane", LastName: "Doe",
+	})
+	var apiErr *pterodactyl.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("CreateUser() with a duplicate email error = %v, want a 422 APIError", err)
+	}
+
+	updated, err := c.Application().UpdateUser(ctx, user.ID, application.UpdateUserRequest{FirstName: "Janet"})
+	if err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	if updated.FirstName != "Janet" || updated.Email != "jane@example.com" {
+		t.Errorf("UpdateUser() = %+v, want only the first name changed", updated)
+	}
+
+	if err := c.Application().DeleteUser(ctx, user.ID); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	if _, err := c.Application().GetUser(ctx, user.ID); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("GetUser() after delete error = %v, want a 404 APIError", err)
+	}
+}
+
+func TestPanel_Pagination(t *testing.T) {
+	p, c := newTestPanel(t)
+	for i := 0; i < 5; i++ {
+		p.AddServer(models.Server{})
+	}
+
+	servers, paginator, err := c.Application().ListServers(context.Background(), pagination.ListOptions{PerPage: 2})
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+	if len(servers) != 2 || paginator.TotalPages() != 3 {
+		t.Fatalf("ListServers() = %d servers, %d pages, want 2 servers and 3 pages", len(servers), paginator.TotalPages())
+	}
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			t.Fatalf("NextPage() error = %v", err)
+		}
+		servers = append(servers, page...)
+	}
+	if len(servers) != 5 {
+		t.Errorf("paginated %d servers, want 5", len(servers))
+	}
+}
+
+func TestPanel_NodeAllocations(t *testing.T) {
+	_, c := newTestPanel(t)
+	ctx := context.Background()
+
+	err := c.Application().CreateNodeAllocations(ctx, 2, application.CreateNodeAllocationRequest{
+		IP:    "10.0.0.1",
+		Ports: []string{"27015", "27020-27022"},
+	})
+	if err != nil {
+		t.Fatalf("CreateNodeAllocations() error = %v", err)
+	}
+
+	allocations, _, err := c.Application().ListNodeAllocations(ctx, 2, pagination.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListNodeAllocations() error = %v", err)
+	}
+	if len(allocations) != 6 {
+		t.Errorf("ListNodeAllocations() returned %d allocations, want 6", len(allocations))
+	}
+}
+
+func TestPanel_Power(t *testing.T) {
+	p, c := newTestPanel(t)
+	server := p.AddServer(models.Server{Name: "Lobby"})
+	ctx := context.Background()
+
+	if err := c.Client().SendPowerAction(ctx, server.Identifier, "start"); err != nil {
+		t.Fatalf("SendPowerAction() error = %v", err)
+	}
+	stats, err := c.Client().GetServerResources(ctx, server.Identifier)
+	if err != nil {
+		t.Fatalf("GetServerResources() error = %v", err)
+	}
+	if stats.CurrentState != "running" {
+		t.Errorf("CurrentState = %q, want running", stats.CurrentState)
+	}
+
+	if err := c.Client().SendCommand(ctx, server.Identifier, "say hello"); err != nil {
+		t.Fatalf("SendCommand() error = %v", err)
+	}
+	if got := p.Commands(server.Identifier); len(got) != 1 || got[0] != "say hello" {
+		t.Errorf("Commands() = %v, want [say hello]", got)
+	}
+
+	p.SetPowerHandler(func(identifier, signal string) []string { return []string{"stopping"} })
+	if err := c.Client().SendPowerAction(ctx, server.Identifier, "stop"); err != nil {
+		t.Fatalf("SendPowerAction() error = %v", err)
+	}
+	if got := p.State(server.Identifier); got != "stopping" {
+		t.Errorf("State() = %q, want stopping", got)
+	}
+}
+
+func TestPanel_SuspendedServer(t *testing.T) {
+	p, c := newTestPanel(t)
+	server := p.AddServer(models.Server{Name: "Lobby"})
+	ctx := context.Background()
+
+	if err := c.Application().SuspendServer(ctx, server.ID); err != nil {
+		t.Fatalf("SuspendServer() error = %v", err)
+	}
+	err := c.Client().SendPowerAction(ctx, server.Identifier, "start")
+	var apiErr *pterodactyl.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Fatalf("SendPowerAction() error = %v, want a 409 APIError", err)
+	}
+	if apiErr.Errors[0].Code != "ServerStateConflictException" {
+		t.Errorf("error code = %q, want ServerStateConflictException", apiErr.Errors[0].Code)
+	}
+}
+
+func TestPanel_Files(t *testing.T) {
+	p, c := newTestPanel(t)
+	server := p.AddServer(models.Server{Name: "Lobby"})
+	id := server.Identifier
+	ctx := context.Background()
+
+	if err := c.Client().WriteFile(ctx, id, "/config/server.properties", "motd=hi"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if got, _ := p.File(id, "config/server.properties"); got != "motd=hi" {
+		t.Errorf("File() = %q, want motd=hi", got)
+	}
+
+	content, err := c.Client().GetFileContents(ctx, id, "/config/server.properties")
+	if err != nil || content != "motd=hi" {
+		t.Errorf("GetFileContents() = %q, %v, want motd=hi", content, err)
+	}
+
+	files, err := c.Client().ListFiles(ctx, id, "/")
+	if err != nil {
+		t.Fatalf("ListFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "config" || files[0].IsFile {
+		t.Errorf("ListFiles(/) = %+v, want the config directory", files)
+	}
+
+	if err := c.Client().CopyFile(ctx, id, "/config/server.properties"); err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	if _, ok := p.File(id, "/config/server copy.properties"); !ok {
+		t.Error("CopyFile() did not create \"server copy.properties\"")
+	}
+
+	if err := c.Client().RenameFile(ctx, id, "/", "config", "cfg"); err != nil {
+		t.Fatalf("RenameFile() error = %v", err)
+	}
+	if _, ok := p.File(id, "/cfg/server.properties"); !ok {
+		t.Error("RenameFile() did not move the directory contents")
+	}
+
+	signed, err := c.Client().GetDownloadURL(ctx, id, "/cfg/server.properties")
+	if err != nil {
+		t.Fatalf("GetDownloadURL() error = %v", err)
+	}
+	resp, err := http.Get(signed.URL)
+	if err != nil {
+		t.Fatalf("download error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "motd=hi" {
+		t.Errorf("downloaded %q, want motd=hi", body)
+	}
+
+	if err := c.Client().DeleteFiles(ctx, id, "/", []string{"cfg"}); err != nil {
+		t.Fatalf("DeleteFiles() error = %v", err)
+	}
+	if files, _ := c.Client().ListFiles(ctx, id, "/"); len(files) != 0 {
+		t.Errorf("ListFiles() after delete = %+v, want none", files)
+	}
+}
+
+func TestPanel_Backups(t *testing.T) {
+	p, c := newTestPanel(t)
+	server := p.AddServer(models.Server{Name: "Lobby", FeatureLimits: models.FeatureLimits{Backups: 1}})
+	id := server.Identifier
+	ctx := context.Background()
+	p.SetFile(id, "world.dat", "v1")
+
+	backup, err := c.Client().CreateBackup(ctx, id, client.CreateBackupRequest{Name: "nightly"})
+	if err != nil {
+		t.Fatalf("CreateBackup() error = %v", err)
+	}
+	if !backup.IsSuccessful || backup.CompletedAt == nil {
+		t.Errorf("backup = %+v, want a completed backup", backup)
+	}
+
+	_, err = c.Client().CreateBackup(ctx, id, client.CreateBackupRequest{})
+	var apiErr *pterodactyl.APIError
+	if !errors.As(err, &apiErr) || apiErr.Errors[0].Code != "TooManyBackupsException" {
+		t.Errorf("CreateBackup() over the limit error = %v, want TooManyBackupsException", err)
+	}
+
+	p.SetFile(id, "world.dat", "v2")
+	if err := c.Client().RestoreBackup(ctx, id, backup.UUID, true); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if got, _ := p.File(id, "world.dat"); got != "v1" {
+		t.Errorf("File() after restore = %q, want v1", got)
+	}
+
+	if err := c.Client().DeleteBackup(ctx, id, backup.UUID); err != nil {
+		t.Fatalf("DeleteBackup() error = %v", err)
+	}
+	if got := p.Backups(id); len(got) != 0 {
+		t.Errorf("Backups() = %+v, want none", got)
+	}
+}
+
+func TestPanel_Schedules(t *testing.T) {
+	p, c := newTestPanel(t)
+	server := p.AddServer(models.Server{Name: "Lobby"})
+	id := server.Identifier
+	ctx := context.Background()
+
+	schedule, err := c.Client().CreateSchedule(ctx, id, client.CreateScheduleRequest{
+		Name: "restart", Minute: "0", Hour: "4", DayOfMonth: "*", Month: "*", DayOfWeek: "*", IsActive: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+	for _, task := range []client.CreateScheduleTaskRequest{
+		{Action: "power", Payload: "start"},
+		{Action: "command", Payload: "say restarted"},
+	} {
+		if _, err := c.Client().CreateScheduleTask(ctx, id, schedule.ID, task); err != nil {
+			t.Fatalf("CreateScheduleTask() error = %v", err)
+		}
+	}
+
+	if err := c.Client().ExecuteSchedule(ctx, id, schedule.ID); err != nil {
+		t.Fatalf("ExecuteSchedule() error = %v", err)
+	}
+	if got := p.State(id); got != "running" {
+		t.Errorf("State() = %q, want running", got)
+	}
+	if got := p.Commands(id); len(got) != 1 || got[0] != "say restarted" {
+		t.Errorf("Commands() = %v, want [say restarted]", got)
+	}
+
+	got, err := c.Client().GetSchedule(ctx, id, schedule.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	if got.LastRunAt == nil || got.Tasks == nil || len(*got.Tasks) != 2 {
+		t.Errorf("GetSchedule() = %+v, want a run time and 2 tasks", got)
+	}
+}
+
+func TestPanel_Databases(t *testing.T) {
+	p, c := newTestPanel(t)
+	server := p.AddServer(models.Server{Name: "Lobby", FeatureLimits: models.FeatureLimits{Databases: 1}})
+	ctx := context.Background()
+
+	db, err := c.Client().CreateDatabase(ctx, server.Identifier, "stats", "")
+	if err != nil {
+		t.Fatalf("CreateDatabase() error = %v", err)
+	}
+	if db.Password == nil || db.Password.Attributes.Password == "" {
+		t.Error("CreateDatabase() did not return a password")
+	}
+	if _, err := c.Client().CreateDatabase(ctx, server.Identifier, "other", ""); err == nil {
+		t.Error("CreateDatabase() over the limit succeeded")
+	}
+
+	list, err := c.Client().ListDatabases(ctx, server.Identifier)
+	if err != nil {
+		t.Fatalf("ListDatabases() error = %v", err)
+	}
+	if len(list) != 1 || list[0].Password != nil {
+		t.Errorf("ListDatabases() = %+v, want one database without a password", list)
+	}
+}
+
+func TestPanel_APIKeys(t *testing.T) {
+	p, c := newTestPanel(t)
+	p.RequireAPIKeys(DefaultAPIKey)
+	ctx := context.Background()
+
+	key, err := c.Client().CreateAPIKey(ctx, "deploy", nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+
+	rotated, err := p.Client(pterodactyl.WithAPIKey(key.Meta.SecretToken))
+	if err != nil {
+		t.Fatalf("Client() error = %v", err)
+	}
+	account, err := rotated.Client().GetAccount(ctx)
+	if err != nil {
+		t.Fatalf("GetAccount() with the new key error = %v", err)
+	}
+	if account.Username != "admin" {
+		t.Errorf("GetAccount() = %q, want admin", account.Username)
+	}
+
+	if err := c.Client().DeleteAPIKey(ctx, key.Identifier); err != nil {
+		t.Fatalf("DeleteAPIKey() error = %v", err)
+	}
+	_, err = rotated.Client().GetAccount(ctx)
+	var apiErr *pterodactyl.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GetAccount() with a deleted key error = %v, want a 401 APIError", err)
+	}
+}
+
+func TestPanel_Requests(t *testing.T) {
+	p, c := newTestPanel(t)
+	if _, err := c.Application().GetNode(context.Background(), 2); err != nil {
+		t.Fatalf("GetNode() error = %v", err)
+	}
+
+	requests := p.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Requests() returned %d requests, want 1", len(requests))
+	}
+	if requests[0].Method != http.MethodGet || requests[0].Path != "application/nodes/2" {
+		t.Errorf("request = %s %s, want GET application/nodes/2", requests[0].Method, requests[0].Path)
+	}
+	if requests[0].Header.Get("Authorization") != "Bearer "+DefaultApplicationKey {
+		t.Errorf("Authorization = %q, want the default application key", requests[0].Header.Get("Authorization"))
+	}
+}
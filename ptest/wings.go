@@ -0,0 +1,226 @@
+package ptest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/idanyas/go-pterodactyl/models"
+)
+
+// writeTimeout bounds how long a broadcast waits for a slow WebSocket client.
+const writeTimeout = 5 * time.Second
+
+// message is a Wings WebSocket message.
+type message struct {
+	Event string   `json:"event"`
+	Args  []string `json:"args,omitempty"`
+}
+
+// wings is a fake Wings daemon serving the server console WebSocket.
+type wings struct {
+	panel *Panel
+
+	mu     sync.Mutex
+	tokens map[string]string // token -> server identifier
+	conns  map[string]map[*websocket.Conn]struct{}
+}
+
+func newWings(p *Panel) *wings {
+	return &wings{
+		panel:  p,
+		tokens: make(map[string]string),
+		conns:  make(map[string]map[*websocket.Conn]struct{}),
+	}
+}
+
+// register registers the WebSocket handler.
+func (wg *wings) register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /wings/servers/{server}/ws", wg.serve)
+}
+
+// issue returns a new token authorising a WebSocket connection to a server.
+func (wg *wings) issue(identifier string) string {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+	token := randomHex(16)
+	wg.tokens[token] = identifier
+	return token
+}
+
+// serve handles a WebSocket connection. Clients must authenticate with a token
+// from the websocket endpoint before other events are accepted.
+func (wg *wings) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+
+	identifier := r.PathValue("server")
+	ctx := r.Context()
+	authenticated := false
+	defer wg.remove(identifier, conn)
+
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		var msg message
+		if json.Unmarshal(data, &msg) != nil {
+			continue
+		}
+		var arg string
+		if len(msg.Args) > 0 {
+			arg = msg.Args[0]
+		}
+
+		if msg.Event == "auth" {
+			wg.mu.Lock()
+			valid := wg.tokens[arg] == identifier
+			wg.mu.Unlock()
+			if !valid {
+				send(ctx, conn, "jwt error", "invalid token")
+				continue
+			}
+			if !authenticated {
+				authenticated = true
+				wg.add(identifier, conn)
+			}
+			send(ctx, conn, "auth success")
+			send(ctx, conn, "status", wg.panel.State(identifier))
+			continue
+		}
+		if !authenticated {
+			continue
+		}
+
+		switch msg.Event {
+		case "set state":
+			if validSignal(arg) {
+				wg.panel.power(identifier, arg)
+			}
+		case "send command":
+			wg.panel.mu.Lock()
+			if rt, ok := wg.panel.runtime[identifier]; ok && rt.state != "offline" {
+				rt.commands = append(rt.commands, arg)
+			}
+			wg.panel.mu.Unlock()
+		case "send stats":
+			wg.panel.mu.Lock()
+			rt, ok := wg.panel.runtime[identifier]
+			var state string
+			var resources models.Resources
+			if ok {
+				state, resources = rt.state, rt.resources
+			}
+			wg.panel.mu.Unlock()
+			if ok {
+				send(ctx, conn, "stats", statsArg(state, resources))
+			}
+		}
+	}
+}
+
+func (wg *wings) add(identifier string, conn *websocket.Conn) {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+	if wg.conns[identifier] == nil {
+		wg.conns[identifier] = make(map[*websocket.Conn]struct{})
+	}
+	wg.conns[identifier][conn] = struct{}{}
+}
+
+func (wg *wings) remove(identifier string, conn *websocket.Conn) {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+	delete(wg.conns[identifier], conn)
+}
+
+// listeners returns the authenticated connections of a server.
+func (wg *wings) listeners(identifier string) []*websocket.Conn {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+	conns := make([]*websocket.Conn, 0, len(wg.conns[identifier]))
+	for c := range wg.conns[identifier] {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+// broadcast sends an event to every authenticated connection of a server.
+func (wg *wings) broadcast(identifier, event string, args ...string) {
+	for _, c := range wg.listeners(identifier) {
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		send(ctx, c, event, args...)
+		cancel()
+	}
+}
+
+// broadcastStats sends a stats event to every authenticated connection of a server.
+func (wg *wings) broadcastStats(identifier, state string, r models.Resources) {
+	wg.broadcast(identifier, "stats", statsArg(state, r))
+}
+
+// disconnect closes all connections of a server.
+func (wg *wings) disconnect(identifier string) {
+	for _, c := range wg.listeners(identifier) {
+		c.Close(websocket.StatusGoingAway, "server disconnected")
+	}
+}
+
+// expire revokes all tokens of a server and notifies its connections.
+func (wg *wings) expire(identifier string) {
+	wg.mu.Lock()
+	for token, id := range wg.tokens {
+		if id == identifier {
+			delete(wg.tokens, token)
+		}
+	}
+	wg.mu.Unlock()
+	wg.broadcast(identifier, "token expired")
+}
+
+// closeAll closes every connection.
+func (wg *wings) closeAll() {
+	wg.mu.Lock()
+	identifiers := make([]string, 0, len(wg.conns))
+	for identifier := range wg.conns {
+		identifiers = append(identifiers, identifier)
+	}
+	wg.mu.Unlock()
+	for _, identifier := range identifiers {
+		wg.disconnect(identifier)
+	}
+}
+
+// send writes a single event to a connection, ignoring errors.
+func send(ctx context.Context, conn *websocket.Conn, event string, args ...string) {
+	data, _ := json.Marshal(message{Event: event, Args: args})
+	conn.Write(ctx, websocket.MessageText, data)
+}
+
+// statsArg encodes resources and state like the stats event sent by Wings.
+func statsArg(state string, r models.Resources) string {
+	data, _ := json.Marshal(struct {
+		models.Resources
+		State string `json:"state"`
+	}{r, state})
+	return string(data)
+}
+
+// DisconnectWebSockets closes all WebSocket connections of a server, for
+// example to test reconnection.
+func (p *Panel) DisconnectWebSockets(identifier string) {
+	p.wings.disconnect(identifier)
+}
+
+// ExpireWebSocketTokens revokes the WebSocket tokens issued for a server and
+// sends a "token expired" event to its connections.
+func (p *Panel) ExpireWebSocketTokens(identifier string) {
+	p.wings.expire(identifier)
+}
@@ -0,0 +1,97 @@
+package ptest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/helpers"
+	"github.com/idanyas/go-pterodactyl/models"
+	"github.com/idanyas/go-pterodactyl/websocket"
+)
+
+// nextEvent returns the next event of type T, skipping others.
+func nextEvent[T websocket.Event](t *testing.T, events <-chan websocket.Event) T {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatal("event channel closed")
+			}
+			if e, ok := event.(T); ok {
+				return e
+			}
+		case <-timeout:
+			var zero T
+			t.Fatalf("timed out waiting for %T", zero)
+		}
+	}
+}
+
+func TestWings_Events(t *testing.T) {
+	p, c := newTestPanel(t)
+	server := p.AddServer(models.Server{Name: "Lobby"})
+	id := server.Identifier
+
+	conn, err := c.Client().ConnectWebSocket(context.Background(), id)
+	if err != nil {
+		t.Fatalf("ConnectWebSocket() error = %v", err)
+	}
+	defer conn.Close()
+	events := conn.Events()
+
+	if e := nextEvent[*websocket.StatusEvent](t, events); e.Status != "offline" {
+		t.Errorf("initial status = %q, want offline", e.Status)
+	}
+
+	if err := conn.SetState("start"); err != nil {
+		t.Fatalf("SetState() error = %v", err)
+	}
+	for _, want := range []string{"starting", "running"} {
+		if e := nextEvent[*websocket.StatusEvent](t, events); e.Status != want {
+			t.Errorf("status = %q, want %q", e.Status, want)
+		}
+	}
+
+	p.SetResources(id, models.Resources{MemoryBytes: 512})
+	if e := nextEvent[*websocket.StatsEvent](t, events); e.Stats.MemoryBytes != 512 {
+		t.Errorf("MemoryBytes = %d, want 512", e.Stats.MemoryBytes)
+	}
+
+	p.Console(id, "Done (1.2s)!")
+	if e := nextEvent[*websocket.ConsoleOutputEvent](t, events); e.Line != "Done (1.2s)!" {
+		t.Errorf("Line = %q, want the console line", e.Line)
+	}
+
+	if err := conn.SendCommand("list"); err != nil {
+		t.Fatalf("SendCommand() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(p.Commands(id)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := p.Commands(id); len(got) != 1 || got[0] != "list" {
+		t.Errorf("Commands() = %v, want [list]", got)
+	}
+
+	p.ExpireWebSocketTokens(id)
+	nextEvent[*websocket.TokenExpiredEvent](t, events)
+}
+
+func TestWings_StateWaiter(t *testing.T) {
+	p, c := newTestPanel(t)
+	server := p.AddServer(models.Server{Name: "Lobby"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	waiter := helpers.NewStateWaiter(c.Client())
+	if err := waiter.StartAndWait(ctx, server.Identifier, helpers.WaitOptions{}); err != nil {
+		t.Fatalf("StartAndWait() error = %v", err)
+	}
+	if got := p.State(server.Identifier); got != "running" {
+		t.Errorf("State() = %q, want running", got)
+	}
+}
@@ -0,0 +1,215 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 30 * time.Second
+	defaultHalfOpenProbes   = 1
+)
+
+// ErrCircuitOpen is returned without contacting the panel while the circuit
+// breaker for its host is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker for one host.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all requests with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through to
+	// test whether the host has recovered.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerOption is a functional option for configuring a CircuitBreaker.
+type CircuitBreakerOption func(*CircuitBreaker)
+
+// WithFailureThreshold sets the number of consecutive failures that open the
+// circuit. It defaults to 5.
+func WithFailureThreshold(n int) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		if n > 0 {
+			b.threshold = n
+		}
+	}
+}
+
+// WithOpenTimeout sets how long the circuit stays open before probe requests
+// are let through. It defaults to 30 seconds.
+func WithOpenTimeout(d time.Duration) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		if d > 0 {
+			b.openTimeout = d
+		}
+	}
+}
+
+// WithHalfOpenProbes sets the number of concurrent probe requests allowed
+// while half-open. It defaults to 1.
+func WithHalfOpenProbes(n int) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		if n > 0 {
+			b.probes = n
+		}
+	}
+}
+
+// WithStateChange sets a function called whenever the circuit of a host
+// changes state. It is called synchronously and must not block.
+func WithStateChange(fn func(host string, from, to CircuitState)) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		b.onChange = fn
+	}
+}
+
+// CircuitBreaker stops sending requests to a panel host after repeated
+// failures. Network errors and 5xx responses count as failures; any other
+// response resets the count. Once the threshold is reached the circuit opens
+// and requests fail with ErrCircuitOpen. After the open timeout, probe
+// requests are let through: a successful probe closes the circuit and a
+// failed one opens it again.
+//
+// Each host has its own circuit. A CircuitBreaker is safe for concurrent use.
+type CircuitBreaker struct {
+	threshold   int
+	openTimeout time.Duration
+	probes      int
+	onChange    func(host string, from, to CircuitState)
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the state of a single host.
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	inFlight int // probe requests while half-open
+}
+
+// NewCircuitBreaker creates a CircuitBreaker.
+func NewCircuitBreaker(opts ...CircuitBreakerOption) *CircuitBreaker {
+	b := &CircuitBreaker{
+		threshold:   defaultFailureThreshold,
+		openTimeout: defaultOpenTimeout,
+		probes:      defaultHalfOpenProbes,
+		circuits:    make(map[string]*circuit),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// WithCircuitBreaker stops requests to failing hosts with a CircuitBreaker.
+func WithCircuitBreaker(b *CircuitBreaker) TransportOption {
+	return func(t *Transport) {
+		t.breaker = b
+	}
+}
+
+// State returns the state of the circuit of a host.
+func (b *CircuitBreaker) State(host string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[host]
+	if !ok {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= b.openTimeout {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// allow reports whether a request to host may be sent, and whether it took
+// one of the probe slots of a half-open circuit. Every allowed request must be
+// followed by a call to done.
+func (b *CircuitBreaker) allow(host string) (probe bool, err error) {
+	b.mu.Lock()
+	c := b.circuits[host]
+	if c == nil {
+		c = &circuit{}
+		b.circuits[host] = c
+	}
+	from := c.state
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= b.openTimeout {
+		c.state = CircuitHalfOpen
+		c.inFlight = 0
+	}
+	switch {
+	case c.state == CircuitOpen:
+		err = ErrCircuitOpen
+	case c.state == CircuitHalfOpen && c.inFlight >= b.probes:
+		err = ErrCircuitOpen
+	case c.state == CircuitHalfOpen:
+		c.inFlight++
+		probe = true
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	b.changed(host, from, to)
+	return probe, err
+}
+
+// done records the outcome of a request allowed by allow, releasing its probe
+// slot if it took one. A request that was abandoned by the caller neither
+// counts as a success nor as a failure.
+func (b *CircuitBreaker) done(ctx context.Context, host string, probe bool, resp *http.Response, err error) {
+	b.mu.Lock()
+	c := b.circuits[host]
+	from := c.state
+	// A probe of an earlier half-open period holds no slot of this one.
+	if probe && c.state == CircuitHalfOpen && c.inFlight > 0 {
+		c.inFlight--
+	}
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// Cancelled by the caller; says nothing about the host.
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		c.failures++
+		if c.state == CircuitHalfOpen || c.failures >= b.threshold {
+			c.state = CircuitOpen
+			c.openedAt = time.Now()
+		}
+	default:
+		c.failures = 0
+		c.state = CircuitClosed
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	b.changed(host, from, to)
+}
+
+func (b *CircuitBreaker) changed(host string, from, to CircuitState) {
+	if from != to && b.onChange != nil {
+		b.onChange(host, from, to)
+	}
+}
@@ -0,0 +1,162 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var mu sync.Mutex
+	var changes []string
+	breaker := NewCircuitBreaker(
+		WithFailureThreshold(3),
+		WithOpenTimeout(50*time.Millisecond),
+		WithStateChange(func(host string, from, to CircuitState) {
+			mu.Lock()
+			defer mu.Unlock()
+			if host != u.Host {
+				t.Errorf("state change for host %q, want %q", host, u.Host)
+			}
+			changes = append(changes, fmt.Sprintf("%s->%s", from, to))
+		}),
+	)
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent",
+		WithMaxRetries(1), WithCircuitBreaker(breaker))
+	client := &http.Client{Transport: tp}
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("client.Get failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if got := breaker.State(u.Host); got != CircuitOpen {
+		t.Fatalf("State() = %s after 3 failures, want open", got)
+	}
+
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests to reach the server, got %d", requests)
+	}
+
+	// A failed probe opens the circuit again.
+	time.Sleep(60 * time.Millisecond)
+	if got := breaker.State(u.Host); got != CircuitHalfOpen {
+		t.Fatalf("State() = %s after the open timeout, want half-open", got)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("client.Get failed: %v", err)
+	}
+	resp.Body.Close()
+	if got := breaker.State(u.Host); got != CircuitOpen {
+		t.Fatalf("State() = %s after a failed probe, want open", got)
+	}
+
+	// A successful probe closes it.
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("client.Get failed: %v", err)
+	}
+	resp.Body.Close()
+	if got := breaker.State(u.Host); got != CircuitClosed {
+		t.Fatalf("State() = %s after a successful probe, want closed", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{
+		"closed->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->closed",
+	}
+	if fmt.Sprint(changes) != fmt.Sprint(want) {
+		t.Errorf("state changes = %v, want %v", changes, want)
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	breaker := NewCircuitBreaker(WithFailureThreshold(2))
+	fail := &http.Response{StatusCode: http.StatusInternalServerError}
+	ok := &http.Response{StatusCode: http.StatusNotFound}
+
+	for _, resp := range []*http.Response{fail, ok, fail, ok, fail} {
+		if _, err := breaker.allow("panel"); err != nil {
+			t.Fatalf("allow: %v", err)
+		}
+		breaker.done(t.Context(), "panel", false, resp, nil)
+	}
+	if got := breaker.State("panel"); got != CircuitClosed {
+		t.Errorf("State() = %s, want closed", got)
+	}
+	if got := breaker.State("other"); got != CircuitClosed {
+		t.Errorf("State() of an unknown host = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbes(t *testing.T) {
+	breaker := NewCircuitBreaker(WithFailureThreshold(1), WithOpenTimeout(time.Millisecond), WithHalfOpenProbes(2))
+	breaker.allow("panel")
+	breaker.done(t.Context(), "panel", false, nil, errors.New("connection refused"))
+	time.Sleep(5 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if probe, err := breaker.allow("panel"); err != nil || !probe {
+			t.Fatalf("probe %d: probe = %v, err = %v", i+1, probe, err)
+		}
+	}
+	if _, err := breaker.allow("panel"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("third concurrent probe: expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenIgnoresEarlierRequests(t *testing.T) {
+	breaker := NewCircuitBreaker(WithFailureThreshold(1), WithOpenTimeout(time.Millisecond), WithHalfOpenProbes(1))
+	fail := &http.Response{StatusCode: http.StatusInternalServerError}
+
+	// Two requests are let through while closed; the first one opens the
+	// circuit and the second is abandoned once it is half-open.
+	breaker.allow("panel")
+	slow, _ := breaker.allow("panel")
+	breaker.done(t.Context(), "panel", false, fail, nil)
+	time.Sleep(5 * time.Millisecond)
+	probe, err := breaker.allow("panel")
+	if err != nil || !probe {
+		t.Fatalf("probe: probe = %v, err = %v", probe, err)
+	}
+	cancelled, cancel := context.WithCancel(t.Context())
+	cancel()
+	breaker.done(cancelled, "panel", slow, nil, context.Canceled)
+
+	// The probe slot is still taken, so no second probe gets through.
+	if _, err := breaker.allow("panel"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second probe: expected ErrCircuitOpen, got %v", err)
+	}
+}
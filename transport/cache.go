@@ -0,0 +1,310 @@
+package transport
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultCacheSize = 1000
+
+// CacheEntry is a response stored by a Cache.
+type CacheEntry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// ETag is the entity tag of the response, used to revalidate the entry
+	// once it expires.
+	ETag string
+	// Expires is when the entry must be revalidated or fetched again.
+	Expires time.Time
+	// Resource and Generation tie the entry to the writes that invalidate it.
+	Resource   string
+	Generation uint64
+}
+
+// CacheStore holds cached responses. Keys do not contain API keys. A
+// CacheStore must be safe for concurrent use.
+type CacheStore interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// LRUCacheStore is an in-memory CacheStore that evicts the least recently used
+// entry once it holds its maximum number of entries.
+type LRUCacheStore struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+}
+
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCacheStore creates an LRUCacheStore holding at most size entries.
+func NewLRUCacheStore(size int) *LRUCacheStore {
+	if size <= 0 {
+		size = defaultCacheSize
+	}
+	return &LRUCacheStore{size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// Get returns the entry stored under key and marks it as recently used.
+func (s *LRUCacheStore) Get(key string) (*CacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	s.order.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true
+}
+
+// Set stores entry under key, evicting the least recently used entry if the
+// store is full.
+func (s *LRUCacheStore) Set(key string, entry *CacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		s.order.MoveToFront(el)
+		return
+	}
+	s.entries[key] = s.order.PushFront(&lruItem{key: key, entry: entry})
+	if s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+// Delete removes the entry stored under key.
+func (s *LRUCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[key]; ok {
+		s.order.Remove(el)
+		delete(s.entries, key)
+	}
+}
+
+// Len returns the number of stored entries.
+func (s *LRUCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// CacheOption is a functional option for configuring a Cache.
+type CacheOption func(*Cache)
+
+// WithCacheStore sets where responses are stored. Defaults to an
+// LRUCacheStore of 1000 entries.
+func WithCacheStore(s CacheStore) CacheOption {
+	return func(c *Cache) {
+		if s != nil {
+			c.store = s
+		}
+	}
+}
+
+// WithCacheTTL caches GET responses of endpoints matching pattern for ttl.
+// Patterns are paths below /api/ in which "{name}" matches any segment, such as
+// "client/servers/{server}/startup". When several patterns match, the first
+// one added wins. Endpoints without a matching pattern are not cached.
+func WithCacheTTL(pattern string, ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.rules = append(c.rules, cacheRule{segments: strings.Split(strings.Trim(pattern, "/"), "/"), ttl: ttl})
+	}
+}
+
+type cacheRule struct {
+	segments []string
+	ttl      time.Duration
+}
+
+// Cache serves repeated GET requests from stored responses. Cache hits are
+// not sent to the panel and do not count against its rate limit. Expired
+// entries with an ETag are revalidated with If-None-Match, so an unchanged
+// resource costs a request but no body.
+//
+// A POST, PUT, PATCH or DELETE request invalidates the entries of the resource
+// it touches: a server of the Client API together with the server list, or
+// another top-level collection such as application/users. Other changes, such
+// as those made through the Application API to a server cached through the
+// Client API, are seen once the entry expires.
+//
+// Entries are kept apart per API key. A Cache is safe for concurrent use and
+// can be shared between Transports.
+type Cache struct {
+	store CacheStore
+	rules []cacheRule
+
+	mu          sync.Mutex
+	generations map[string]uint64 // resource -> number of invalidations
+}
+
+// NewCache creates a Cache. Without WithCacheTTL options it caches nothing.
+func NewCache(opts ...CacheOption) *Cache {
+	c := &Cache{generations: make(map[string]uint64)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.store == nil {
+		c.store = NewLRUCacheStore(defaultCacheSize)
+	}
+	return c
+}
+
+// WithCache serves GET requests from c when possible.
+func WithCache(c *Cache) TransportOption {
+	return func(t *Transport) {
+		t.cache = c
+	}
+}
+
+// Invalidate drops the cached entries of the resource an API path belongs to,
+// such as "/api/client/servers/abc123".
+func (c *Cache) Invalidate(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range invalidatedResources(path) {
+		c.generations[r]++
+	}
+}
+
+func (c *Cache) generation(resource string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[resource]
+}
+
+// roundTrip serves req from the cache or sends it with send, storing the
+// response if its endpoint is cached. apiKey keeps entries of different keys
+// apart.
+func (c *Cache) roundTrip(req *http.Request, apiKey string, bypass bool, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := send(req)
+		if req.Method != http.MethodHead && req.Method != http.MethodOptions {
+			c.Invalidate(req.URL.Path)
+		}
+		return resp, err
+	}
+
+	ttl, ok := c.ttl(req.URL.Path)
+	if !ok {
+		return send(req)
+	}
+	key := cacheKey(req, apiKey)
+	resource := resourceOf(req.URL.Path)
+	gen := c.generation(resource)
+
+	entry, ok := c.store.Get(key)
+	if ok && (bypass || entry.Resource != resource || entry.Generation != gen) {
+		c.store.Delete(key)
+		entry, ok = nil, false
+	}
+	if ok && time.Now().Before(entry.Expires) {
+		return entry.response(req), nil
+	}
+	if ok && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	resp, err := send(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		refreshed := *entry
+		refreshed.Expires = time.Now().Add(ttl)
+		c.store.Set(key, &refreshed)
+		return refreshed.response(req), nil
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		c.store.Set(key, &CacheEntry{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       body,
+			ETag:       resp.Header.Get("ETag"),
+			Expires:    time.Now().Add(ttl),
+			Resource:   resource,
+			Generation: gen,
+		})
+	}
+	return resp, nil
+}
+
+// ttl returns how long responses of the endpoint at path are cached.
+func (c *Cache) ttl(path string) (time.Duration, bool) {
+	segments := apiSegments(path)
+	if segments == nil {
+		return 0, false
+	}
+	for _, r := range c.rules {
+		if matchSegments(r.segments, segments) {
+			return r.ttl, r.ttl > 0
+		}
+	}
+	return 0, false
+}
+
+// response builds a response to req from the entry.
+func (e *CacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// cacheKey identifies a request by a hash of its API key and its URL.
+func cacheKey(req *http.Request, apiKey string) string {
+	return keyHash(apiKey) + " " + req.URL.String()
+}
+
+// resourceOf returns the resource an API path belongs to: a server of the
+// Client API, such as "client/servers/abc123", or otherwise the first two
+// segments below /api/, such as "application/users".
+func resourceOf(path string) string {
+	segments := apiSegments(path)
+	if len(segments) >= 3 && segments[0] == "client" && segments[1] == "servers" {
+		return strings.Join(segments[:3], "/")
+	}
+	return strings.Join(segments[:min(len(segments), 2)], "/")
+}
+
+// invalidatedResources returns the resources a write to path changes. A
+// change to a Client API server also changes the server list.
+func invalidatedResources(path string) []string {
+	r := resourceOf(path)
+	if strings.HasPrefix(r, "client/servers/") {
+		return []string{r, "client"}
+	}
+	return []string{r}
+}
@@ -0,0 +1,174 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// cacheServer counts requests and answers GETs with the current version of
+// every resource.
+func cacheServer(t *testing.T, etag bool) (*httptest.Server, *int32, *int32) {
+	t.Helper()
+	var requests, version int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Method != http.MethodGet {
+			atomic.AddInt32(&version, 1)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		tag := fmt.Sprintf(`"v%d"`, atomic.LoadInt32(&version))
+		if etag {
+			if r.Header.Get("If-None-Match") == tag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", tag)
+		}
+		fmt.Fprintf(w, "%s %s", r.Header.Get("Authorization"), tag)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests, &version
+}
+
+func cacheGet(t *testing.T, client *http.Client, ctx context.Context, url string) string {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if want := fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)); resp.Status != want {
+		t.Errorf("Status = %q, want %q", resp.Status, want)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestCache_HitsAndInvalidation(t *testing.T) {
+	server, requests, _ := cacheServer(t, false)
+	limiter := NewRateLimiter()
+	cache := NewCache(WithCacheTTL("client/servers/{server}", time.Minute))
+	client := &http.Client{Transport: New(http.DefaultTransport, "key-a", "v1", "test-agent",
+		WithCache(cache), WithRateLimiter(limiter))}
+	ctx := context.Background()
+	url := server.URL + "/api/client/servers/abc123"
+
+	first := cacheGet(t, client, ctx, url)
+	key := RateLimitKey("/api/client/servers/abc123", "key-a")
+	limiter.Update(key, RateLimitInfo{Limit: 60, Remaining: 10, Reset: time.Now().Add(time.Minute)})
+	if got := cacheGet(t, client, ctx, url); got != first {
+		t.Errorf("cached body = %q, want %q", got, first)
+	}
+	if *requests != 1 {
+		t.Errorf("expected 1 request, got %d", *requests)
+	}
+	if state := limiter.State(key); state.Remaining != 10 {
+		t.Errorf("cache hit used the rate limit: %d remaining, want 10", state.Remaining)
+	}
+
+	// Other endpoints are not cached.
+	cacheGet(t, client, ctx, url+"/resources")
+	cacheGet(t, client, ctx, url+"/resources")
+	if *requests != 3 {
+		t.Errorf("expected 3 requests, got %d", *requests)
+	}
+
+	// A write to the server invalidates it.
+	req, _ := http.NewRequest(http.MethodPost, url+"/settings/rename", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do failed: %v", err)
+	}
+	resp.Body.Close()
+	if got := cacheGet(t, client, ctx, url); got == first {
+		t.Errorf("body after write = %q, want a fresh response", got)
+	}
+	if *requests != 5 {
+		t.Errorf("expected 5 requests, got %d", *requests)
+	}
+
+	// NoCache bypasses the cache.
+	cacheGet(t, client, WithRequestOptions(ctx, NoCache()), url)
+	if *requests != 6 {
+		t.Errorf("expected 6 requests, got %d", *requests)
+	}
+}
+
+func TestCache_PerAPIKey(t *testing.T) {
+	server, requests, _ := cacheServer(t, false)
+	cache := NewCache(WithCacheTTL("client/account", time.Minute))
+	tp := New(http.DefaultTransport, "key-a", "v1", "test-agent",
+		WithCache(cache), WithRateLimiter(nil), WithCredentials(ContextCredentials(StaticCredentials("key-a"))))
+	client := &http.Client{Transport: tp}
+	url := server.URL + "/api/client/account"
+
+	a := cacheGet(t, client, context.Background(), url)
+	b := cacheGet(t, client, ContextWithAPIKey(context.Background(), "key-b"), url)
+	if a == b || *requests != 2 {
+		t.Errorf("keys shared a cache entry: %q and %q after %d requests", a, b, *requests)
+	}
+}
+
+func TestCache_ETagRevalidation(t *testing.T) {
+	server, requests, version := cacheServer(t, true)
+	cache := NewCache(WithCacheTTL("client/permissions", time.Nanosecond))
+	client := &http.Client{Transport: New(http.DefaultTransport, "key-a", "v1", "test-agent",
+		WithCache(cache), WithRateLimiter(nil))}
+	ctx := context.Background()
+	url := server.URL + "/api/client/permissions"
+
+	first := cacheGet(t, client, ctx, url)
+	if got := cacheGet(t, client, ctx, url); got != first {
+		t.Errorf("revalidated body = %q, want %q", got, first)
+	}
+	if *requests != 2 {
+		t.Errorf("expected 2 requests, got %d", *requests)
+	}
+
+	// Changed behind the cache's back: the ETag no longer matches.
+	atomic.AddInt32(version, 1)
+	if got := cacheGet(t, client, ctx, url); got == first {
+		t.Errorf("body after change = %q, want a fresh response", got)
+	}
+}
+
+func TestLRUCacheStore(t *testing.T) {
+	s := NewLRUCacheStore(2)
+	s.Set("a", &CacheEntry{})
+	s.Set("b", &CacheEntry{})
+	s.Get("a")
+	s.Set("c", &CacheEntry{})
+	if _, ok := s.Get("b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if _, ok := s.Get("a"); !ok {
+		t.Error("recently used entry was evicted")
+	}
+	s.Delete("a")
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", s.Len())
+	}
+}
+
+func TestResourceOf(t *testing.T) {
+	tests := map[string]string{
+		"/api/client/servers/abc123/startup/variable": "client/servers/abc123",
+		"/api/client/servers/abc123":                  "client/servers/abc123",
+		"/api/client":                                 "client",
+		"/api/client/account/api-keys/x":              "client/account",
+		"/panel/api/application/users/5":              "application/users",
+	}
+	for path, want := range tests {
+		if got := resourceOf(path); got != want {
+			t.Errorf("resourceOf(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/idanyas/go-pterodactyl/internal/redact"
+)
+
+// CassetteMode selects whether a Cassette records or replays interactions.
+type CassetteMode int
+
+const (
+	// ModeReplay serves responses from the cassette file and never contacts
+	// the network. Requests without a matching interaction fail.
+	ModeReplay CassetteMode = iota
+	// ModeRecord forwards requests to the base transport and records each
+	// interaction. Call Save to write the cassette file.
+	ModeRecord
+)
+
+// MatchOn is a set of request attributes compared when replaying.
+type MatchOn int
+
+const (
+	// MatchMethod compares the request method.
+	MatchMethod MatchOn = 1 << iota
+	// MatchPath compares the URL host and path.
+	MatchPath
+	// MatchQuery compares the URL query parameters.
+	MatchQuery
+	// MatchBody compares the request body.
+	MatchBody
+
+	// DefaultMatch compares method, path and query.
+	DefaultMatch = MatchMethod | MatchPath | MatchQuery
+)
+
+// RecordedRequest is a request stored in a cassette.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response stored in a cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a request and the response it received.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// cassetteFile is the on-disk format of a cassette.
+type cassetteFile struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// CassetteOption is a functional option for configuring a Cassette.
+type CassetteOption func(*Cassette)
+
+// WithCassetteTransport sets the transport used to perform requests while
+// recording. It defaults to http.DefaultTransport.
+func WithCassetteTransport(base http.RoundTripper) CassetteOption {
+	return func(c *Cassette) {
+		if base != nil {
+			c.base = base
+		}
+	}
+}
+
+// WithMatchOn sets the request attributes compared when replaying.
+func WithMatchOn(m MatchOn) CassetteOption {
+	return func(c *Cassette) {
+		if m != 0 {
+			c.match = m
+		}
+	}
+}
+
+// Cassette is an http.RoundTripper that records panel interactions to a
+// fixture file and replays them in tests.
+//
+// Install it beneath the client transport with WithHTTPClient so recorded
+// requests include the headers sent to the panel:
+//
+//	cassette, err := transport.NewCassette("testdata/servers.json", transport.ModeReplay)
+//	client, err := pterodactyl.New(url, pterodactyl.WithHTTPClient(&http.Client{Transport: cassette}))
+//
+// The Authorization header, cookies and token query parameters are removed
+// before interactions are stored, as are credentials in response bodies such
+// as signed download URLs, WebSocket tokens and database passwords.
+//
+// When replaying, interactions are consumed in recorded order, so repeated
+// requests receive successive responses.
+type Cassette struct {
+	path  string
+	mode  CassetteMode
+	base  http.RoundTripper
+	match MatchOn
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// NewCassette creates a Cassette backed by the file at path. In ModeReplay the
+// file is loaded immediately and must exist.
+func NewCassette(path string, mode CassetteMode, opts ...CassetteOption) (*Cassette, error) {
+	c := &Cassette{
+		path:  path,
+		mode:  mode,
+		base:  http.DefaultTransport,
+		match: DefaultMatch,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		var f cassetteFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+		}
+		c.interactions = f.Interactions
+		c.used = make([]bool, len(f.Interactions))
+	}
+
+	return c, nil
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]Interaction, len(c.interactions))
+	for i, in := range c.interactions {
+		out[i] = *in
+	}
+	return out
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(cassetteFile{Interactions: c.interactions}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// RoundTrip records or replays a single HTTP transaction.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    redact.URL(req.URL.String()),
+		Header: redactHeader(req.Header, headerAuth, "Cookie"),
+		// Redacted in both modes, so replayed bodies still match.
+		Body: redact.String(body),
+	}
+
+	if c.mode == ModeReplay {
+		return c.replay(req, recorded)
+	}
+	return c.record(req, recorded)
+}
+
+func (c *Cassette) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := c.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, &Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header, "Set-Cookie"),
+			Body:       redact.String(string(data)),
+		},
+	})
+	c.mu.Unlock()
+
+	return resp, nil
+}
+
+func (c *Cassette) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, in := range c.interactions {
+		if c.used[i] || !c.matches(in.Request, recorded) {
+			continue
+		}
+		c.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette %s has no interaction for %s %s", c.path, recorded.Method, recorded.URL)
+}
+
+// matches reports whether a recorded request matches an incoming one.
+func (c *Cassette) matches(stored, incoming RecordedRequest) bool {
+	if c.match&MatchMethod != 0 && stored.Method != incoming.Method {
+		return false
+	}
+
+	su, err1 := url.Parse(stored.URL)
+	iu, err2 := url.Parse(incoming.URL)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	if c.match&MatchPath != 0 && (su.Host != iu.Host || su.Path != iu.Path) {
+		return false
+	}
+	// Parsed values compare independently of parameter order.
+	if c.match&MatchQuery != 0 && su.Query().Encode() != iu.Query().Encode() {
+		return false
+	}
+	if c.match&MatchBody != 0 && strings.TrimSpace(stored.Body) != strings.TrimSpace(incoming.Body) {
+		return false
+	}
+	return true
+}
+
+// readRequestBody returns the request body and restores it for the next reader.
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
+
+// redactHeader returns a copy of h without the named headers.
+func redactHeader(h http.Header, names ...string) http.Header {
+	out := h.Clone()
+	for _, name := range names {
+		out.Del(name)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
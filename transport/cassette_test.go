@@ -0,0 +1,188 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/client/servers/abc/files/download":
+			io.WriteString(w, `{"object":"signed_url","attributes":{"url":"https://node.example.com/download/file?token=secret-jwt"}}`)
+		case "/api/client/servers/abc/websocket":
+			io.WriteString(w, `{"data":{"token":"secret-jwt","socket":"wss://node.example.com/ws"}}`)
+		default:
+			io.WriteString(w, `{"object":"server","attributes":{"name":"`+r.URL.Query().Get("include")+`"}}`)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := NewCassette(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("NewCassette: %v", err)
+	}
+	client := &http.Client{Transport: New(rec, "ptlc_secret-key", "v1", "test-agent")}
+
+	urls := []string{
+		server.URL + "/api/client/servers/abc?include=egg",
+		server.URL + "/api/client/servers/abc/files/download?file=%2Fa.txt",
+		server.URL + "/api/client/servers/abc/websocket",
+		server.URL + "/download?token=secret-jwt",
+	}
+	var recorded []string
+	for _, u := range urls {
+		recorded = append(recorded, get(t, client, u))
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"ptlc_secret-key", "secret-jwt", "Authorization"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	server.Close()
+	requests = 0
+	play, err := NewCassette(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("NewCassette: %v", err)
+	}
+	client = &http.Client{Transport: New(play, "other-key", "v1", "test-agent", WithMaxRetries(1))}
+
+	got := get(t, client, urls[0])
+	if got != recorded[0] {
+		t.Errorf("replayed body = %q, want %q", got, recorded[0])
+	}
+	if got := get(t, client, urls[3]); got == "" {
+		t.Error("request with a token was not replayed")
+	}
+	if requests != 0 {
+		t.Errorf("replay made %d requests to the server", requests)
+	}
+
+	// The only interaction for the first URL has been consumed.
+	if _, err := client.Get(urls[0]); err == nil {
+		t.Error("expected an error for an unmatched request")
+	}
+	if _, err := client.Get(server.URL + "/api/client/servers/abc?include=allocations"); err == nil {
+		t.Error("expected an error for a request with a different query")
+	}
+}
+
+func TestCassette_MatchOn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	contents := `{"interactions":[
+		{"request":{"method":"POST","url":"http://panel/api/client/servers/abc/command","body":"{\"command\":\"say a\"}"},"response":{"status_code":204}},
+		{"request":{"method":"POST","url":"http://panel/api/client/servers/abc/command","body":"{\"command\":\"say b\"}"},"response":{"status_code":502}}
+	]}`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		match MatchOn
+		want  int
+	}{
+		{"ignores body by default", DefaultMatch, http.StatusNoContent},
+		{"matches body", DefaultMatch | MatchBody, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCassette(path, ModeReplay, WithMatchOn(tt.match))
+			if err != nil {
+				t.Fatalf("NewCassette: %v", err)
+			}
+			resp, err := (&http.Client{Transport: c}).Post(
+				"http://panel/api/client/servers/abc/command", "application/json",
+				strings.NewReader(`{"command":"say b"}`))
+			if err != nil {
+				t.Fatalf("Post: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestCassette_RedactsRequestBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	body := `{"current_password":"hunter2","password":"hunter3","password_confirmation":"hunter3"}`
+	put := func(c *Cassette) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/client/account/password", strings.NewReader(body))
+		resp, err := (&http.Client{Transport: c}).Do(req)
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	rec, err := NewCassette(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("NewCassette: %v", err)
+	}
+	put(rec)
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter") {
+		t.Errorf("cassette contains a password:\n%s", data)
+	}
+
+	play, err := NewCassette(path, ModeReplay, WithMatchOn(DefaultMatch|MatchBody))
+	if err != nil {
+		t.Fatalf("NewCassette: %v", err)
+	}
+	if resp := put(play); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+}
+
+func TestCassette_MissingFile(t *testing.T) {
+	if _, err := NewCassette(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Fatal("expected an error for a missing cassette")
+	}
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
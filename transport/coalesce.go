@@ -0,0 +1,187 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// uncoalesced lists GET operations that must not share a response, because each
+// call returns a single-use URL, a new token or a new secret.
+var uncoalesced = map[string]bool{
+	"client.GetDownloadURL":       true,
+	"client.GetUploadURL":         true,
+	"client.GetBackupDownloadURL": true,
+	"client.ConnectWebSocket":     true,
+	"client.GetTwoFactorQR":       true,
+}
+
+// WithCoalescing merges identical GET requests that are in flight at the same
+// time, so only one of them is sent and all callers get a copy of its
+// response. Requests are identical when they have the same URL, API key and
+// request options. Each caller stops waiting when its own context is done.
+// Requests with a RetryGuard or CaptureResponse option, and endpoints that
+// issue single-use URLs or tokens, are never merged. Use the NoCoalescing
+// request option to opt out for a call.
+func WithCoalescing(enabled bool) TransportOption {
+	return func(t *Transport) {
+		if enabled {
+			t.flights = &flightGroup{flights: make(map[string]*flight)}
+		} else {
+			t.flights = nil
+		}
+	}
+}
+
+// NoCoalescing sends a GET request on its own even if an identical one is in
+// flight.
+func NoCoalescing() RequestOption {
+	return func(c *requestConfig) {
+		c.noCoalescing = true
+	}
+}
+
+// flightGroup tracks the requests in flight for coalescing.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is a request shared by one or more callers.
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	// deadline is the latest deadline of the callers, unless unbounded is set
+	// because one of them has none.
+	deadline  time.Time
+	unbounded bool
+
+	// Set before done is closed.
+	resp     *http.Response
+	body     []byte
+	err      error
+	attempts int
+}
+
+// flightKey returns the key under which a request shares a flight, or false if
+// it may not share one. Requests only share a flight if everything that
+// affects how it is sent and retried is the same.
+func flightKey(req *http.Request, apiKey string, cfg requestConfig) (string, bool) {
+	if req.Method != http.MethodGet || (req.Body != nil && req.Body != http.NoBody) ||
+		cfg.noCoalescing || cfg.retryGuard != nil || cfg.response != nil {
+		return "", false
+	}
+	if name, _ := Operation(req); uncoalesced[name] {
+		return "", false
+	}
+	return fmt.Sprintf("%s retries=%d wait=%s no-cache=%t", cacheKey(req, apiKey), cfg.maxRetries, cfg.rateLimitMaxWait, cfg.noCache), true
+}
+
+// flightContext is the context of a shared request. It is not bound by the
+// deadline of any one caller, but reports the latest deadline of its callers,
+// so that retry and rate limit waits no caller would live to see still fail
+// fast.
+type flightContext struct {
+	context.Context
+	g *flightGroup
+	f *flight
+}
+
+// Deadline implements context.Context.
+func (ctx flightContext) Deadline() (time.Time, bool) {
+	ctx.g.mu.Lock()
+	defer ctx.g.mu.Unlock()
+	if ctx.f.unbounded {
+		return time.Time{}, false
+	}
+	return ctx.f.deadline, true
+}
+
+// join records the deadline of a caller of f. g.mu must be held.
+func (f *flight) join(ctx context.Context) {
+	f.waiters++
+	deadline, ok := ctx.Deadline()
+	switch {
+	case !ok:
+		f.unbounded = true
+	case deadline.After(f.deadline):
+		f.deadline = deadline
+	}
+}
+
+// do sends req with send unless an identical request is in flight, and returns
+// a copy of the shared response. The shared request runs until it completes or
+// every caller waiting for it has given up; each caller returns when its own
+// context is done. Its attempts are recorded in c.
+func (g *flightGroup) do(req *http.Request, key string, c *call, send func(*http.Request, *call) (*http.Response, error)) (*http.Response, error) {
+	g.mu.Lock()
+	f, ok := g.flights[key]
+	if ok {
+		f.join(req.Context())
+	} else {
+		ctx, cancel := context.WithCancel(context.WithoutCancel(req.Context()))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		f.join(req.Context())
+		g.flights[key] = f
+
+		var fc *call
+		if c != nil {
+			fc = &call{info: c.info}
+		}
+		go g.run(f, key, req.WithContext(flightContext{Context: ctx, g: g, f: f}), fc, send)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if c != nil {
+			c.attempts = f.attempts
+		}
+		if f.err != nil {
+			return nil, f.err
+		}
+		resp := *f.resp
+		resp.Header = f.resp.Header.Clone()
+		resp.Body = io.NopCloser(bytes.NewReader(f.body))
+		resp.Request = req
+		return &resp, nil
+	case <-req.Context().Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			f.cancel()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, req.Context().Err()
+	}
+}
+
+// run sends the shared request and buffers its response for the callers.
+func (g *flightGroup) run(f *flight, key string, req *http.Request, c *call, send func(*http.Request, *call) (*http.Response, error)) {
+	defer f.cancel()
+
+	resp, err := send(req, c)
+	if err == nil {
+		f.body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	f.resp, f.err = resp, err
+	if c != nil {
+		f.attempts = c.attempts
+	}
+
+	g.mu.Lock()
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+	g.mu.Unlock()
+	close(f.done)
+}
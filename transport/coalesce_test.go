@@ -0,0 +1,279 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters blocks until n callers wait for the flights of g.
+func waitForWaiters(t *testing.T, g *flightGroup, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		waiters := 0
+		for _, f := range g.flights {
+			waiters += f.waiters
+		}
+		g.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d callers", n)
+}
+
+func TestTransport_Coalescing(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Header().Set("X-Server", "abc123")
+		io.WriteString(w, `{"attributes":{"identifier":"abc123"}}`)
+	}))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent", WithRateLimiter(nil), WithCoalescing(true))
+	client := &http.Client{Transport: tp}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	bodies := make([]string, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL + "/api/client/servers/abc123/resources")
+			if err != nil {
+				t.Errorf("client.Get failed: %v", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			bodies[i] = resp.Header.Get("X-Server") + " " + string(body)
+		}()
+	}
+	waitForWaiters(t, tp.flights, callers)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	for i, body := range bodies {
+		if body != `abc123 {"attributes":{"identifier":"abc123"}}` {
+			t.Errorf("caller %d got %q", i, body)
+		}
+	}
+}
+
+func TestTransport_CoalescingSeparates(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+	}))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "key-a", "v1", "test-agent", WithRateLimiter(nil), WithCoalescing(true),
+		WithCredentials(ContextCredentials(StaticCredentials("key-a"))))
+	client := &http.Client{Transport: tp}
+
+	guard := func(context.Context) (bool, error) { return true, nil }
+	gets := []struct {
+		ctx  context.Context
+		path string
+	}{
+		{context.Background(), "/api/client/servers/abc123"},
+		{ContextWithAPIKey(context.Background(), "key-b"), "/api/client/servers/abc123"},
+		{WithRequestOptions(context.Background(), NoCoalescing()), "/api/client/servers/abc123"},
+		{WithRequestOptions(context.Background(), NoRetries()), "/api/client/servers/abc123"},
+		{WithRequestOptions(context.Background(), RetryGuard(guard)), "/api/client/servers/abc123"},
+		{WithRequestOptions(context.Background(), CaptureResponse(&ResponseMeta{})), "/api/client/servers/abc123"},
+		{context.Background(), "/api/client/servers/abc123/files/download?file=a"},
+		{context.Background(), "/api/client/servers/abc123/files/download?file=a"},
+	}
+	var wg sync.WaitGroup
+	for _, g := range gets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequestWithContext(g.ctx, http.MethodGet, server.URL+g.path, nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Errorf("client.Do failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) < int32(len(gets)) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if requests != int32(len(gets)) {
+		t.Errorf("expected %d requests, got %d", len(gets), requests)
+	}
+}
+
+func TestTransport_CoalescingCancel(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	cancelled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		select {
+		case <-release:
+			io.WriteString(w, "ok")
+		case <-r.Context().Done():
+			close(cancelled)
+		}
+	}))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent", WithRateLimiter(nil), WithCoalescing(true))
+	client := &http.Client{Transport: tp}
+	url := server.URL + "/api/client/servers/abc123"
+
+	get := func(ctx context.Context) (string, error) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	// The first caller gives up; the second still gets the response.
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := get(ctx)
+		firstErr <- err
+	}()
+	waitForWaiters(t, tp.flights, 1)
+	second := make(chan string, 1)
+	go func() {
+		body, _ := get(context.Background())
+		second <- body
+	}()
+	waitForWaiters(t, tp.flights, 2)
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller got %v, want context.Canceled", err)
+	}
+	close(release)
+	if body := <-second; body != "ok" {
+		t.Errorf("second caller got %q, want ok", body)
+	}
+
+	// When every caller gives up, the request is cancelled.
+	release = make(chan struct{})
+	ctx, cancel = context.WithCancel(context.Background())
+	go get(ctx)
+	waitForWaiters(t, tp.flights, 1)
+	cancel()
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shared request was not cancelled")
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestTransport_CoalescingTimeouts(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent", WithRateLimiter(nil), WithCoalescing(true))
+	client := &http.Client{Transport: tp}
+
+	// Each caller has its own timeout; the first one gives up before the
+	// response arrives without affecting the others.
+	timeouts := []time.Duration{50 * time.Millisecond, 10 * time.Second, 20 * time.Second, 0}
+	errs := make([]error, len(timeouts))
+	var wg sync.WaitGroup
+	for i, timeout := range timeouts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/client/servers/abc123", nil)
+			resp, err := client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+			errs[i] = err
+		}()
+	}
+	waitForWaiters(t, tp.flights, len(timeouts))
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	if !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("first caller got %v, want context.DeadlineExceeded", errs[0])
+	}
+	for i, err := range errs[1:] {
+		if err != nil {
+			t.Errorf("caller %d got %v", i+1, err)
+		}
+	}
+}
+
+func TestTransport_CoalescingDeadline(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent", WithCoalescing(true))
+	client := &http.Client{Transport: tp}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/client/servers/abc123", nil)
+
+	start := time.Now()
+	_, err := client.Do(req)
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("coalesced request did not fail fast: %v", d)
+	}
+	var rateErr *RateLimitedError
+	if !errors.As(err, &rateErr) {
+		t.Errorf("expected a RateLimitedError, got %v", err)
+	}
+}
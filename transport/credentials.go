@@ -0,0 +1,189 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CredentialProvider supplies the API key sent with a request. APIKey is
+// called once per request with the request's context and must be safe for
+// concurrent use. Returning an error fails the request without sending it.
+type CredentialProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// CredentialFunc adapts a function to a CredentialProvider.
+type CredentialFunc func(ctx context.Context) (string, error)
+
+// APIKey calls f.
+func (f CredentialFunc) APIKey(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticCredentials always returns key.
+func StaticCredentials(key string) CredentialProvider {
+	return CredentialFunc(func(context.Context) (string, error) {
+		return key, nil
+	})
+}
+
+// EnvCredentials reads the key from the environment variable name on every
+// request, so changes to the variable take effect immediately.
+func EnvCredentials(name string) CredentialProvider {
+	return CredentialFunc(func(context.Context) (string, error) {
+		return os.Getenv(name), nil
+	})
+}
+
+// FileCredentials reads the key from a file, such as a mounted secret, and
+// reads it again whenever the file's modification time or size changes.
+// Surrounding whitespace is trimmed. A FileCredentials is safe for concurrent
+// use.
+type FileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	key     string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileCredentials creates a FileCredentials reading the key from path.
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// APIKey returns the key in the file, reloading it if the file changed.
+func (f *FileCredentials) APIKey(context.Context) (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat credentials file: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.key != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.key, nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	f.key = string(bytes.TrimSpace(data))
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return f.key, nil
+}
+
+// RotatingCredentials holds a key that can be replaced while requests are in
+// flight. Share one between clients to switch all of them to a new key at
+// once. A RotatingCredentials is safe for concurrent use.
+type RotatingCredentials struct {
+	key atomic.Pointer[string]
+}
+
+// NewRotatingCredentials creates a RotatingCredentials starting with key.
+func NewRotatingCredentials(key string) *RotatingCredentials {
+	r := &RotatingCredentials{}
+	r.key.Store(&key)
+	return r
+}
+
+// APIKey returns the current key.
+func (r *RotatingCredentials) APIKey(context.Context) (string, error) {
+	return *r.key.Load(), nil
+}
+
+// Set replaces the key for all subsequent requests and returns the previous
+// key.
+func (r *RotatingCredentials) Set(key string) (old string) {
+	return *r.key.Swap(&key)
+}
+
+// apiKeyContextKey is the context key for per-request API keys.
+type apiKeyContextKey struct{}
+
+// ContextWithAPIKey returns a context whose requests use key when they go
+// through a ContextCredentials provider. It lets a single client act for
+// different users, for example with each customer's Client API key.
+func ContextWithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// ContextCredentials returns the key set with ContextWithAPIKey, or the key of
+// fallback if the context carries none. fallback may be nil, in which case
+// requests without a key in their context have no key.
+func ContextCredentials(fallback CredentialProvider) CredentialProvider {
+	return CredentialFunc(func(ctx context.Context) (string, error) {
+		if key, ok := ctx.Value(apiKeyContextKey{}).(string); ok && key != "" {
+			return key, nil
+		}
+		if fallback == nil {
+			return "", nil
+		}
+		return fallback.APIKey(ctx)
+	})
+}
+
+// WithCredentials obtains the default API key from p instead of the static key
+// passed to New.
+func WithCredentials(p CredentialProvider) TransportOption {
+	return func(t *Transport) {
+		if p != nil {
+			t.creds = p
+		}
+	}
+}
+
+// WithPathCredentials obtains the API key for requests whose URL path starts
+// with prefix from p. The longest matching prefix wins. If p returns an empty
+// key, such requests fail with ErrNoAPIKey without being sent.
+func WithPathCredentials(prefix string, p CredentialProvider) TransportOption {
+	return func(t *Transport) {
+		t.pathKeys = append(t.pathKeys, pathKey{prefix: prefix, creds: p})
+	}
+}
+
+// WithPathKey uses key instead of the default API key for requests whose URL
+// path starts with prefix, like WithPathCredentials with StaticCredentials.
+func WithPathKey(prefix, key string) TransportOption {
+	return WithPathCredentials(prefix, StaticCredentials(key))
+}
+
+// pathKey is the credential provider for requests under a path prefix.
+type pathKey struct {
+	prefix string
+	creds  CredentialProvider
+}
+
+// key returns the API key for a request.
+func (t *Transport) key(ctx context.Context, path string) (string, error) {
+	var match *pathKey
+	for i, pk := range t.pathKeys {
+		if strings.HasPrefix(path, pk.prefix) && (match == nil || len(pk.prefix) > len(match.prefix)) {
+			match = &t.pathKeys[i]
+		}
+	}
+	if match == nil {
+		key, err := t.creds.APIKey(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get API key: %w", err)
+		}
+		return key, nil
+	}
+
+	key, err := match.creds.APIKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get API key for %s: %w", match.prefix, err)
+	}
+	if key == "" {
+		return "", fmt.Errorf("%w for %s", ErrNoAPIKey, match.prefix)
+	}
+	return key, nil
+}
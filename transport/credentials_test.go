@@ -0,0 +1,115 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	p := EnvCredentials("PTERODACTYL_TEST_KEY")
+	t.Setenv("PTERODACTYL_TEST_KEY", "ptlc_first")
+	if key, _ := p.APIKey(t.Context()); key != "ptlc_first" {
+		t.Errorf("APIKey() = %q, want ptlc_first", key)
+	}
+	t.Setenv("PTERODACTYL_TEST_KEY", "ptlc_second")
+	if key, _ := p.APIKey(t.Context()); key != "ptlc_second" {
+		t.Errorf("APIKey() after change = %q, want ptlc_second", key)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("ptla_first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := NewFileCredentials(path)
+	if key, err := p.APIKey(t.Context()); err != nil || key != "ptla_first" {
+		t.Fatalf("APIKey() = %q, %v; want ptla_first", key, err)
+	}
+
+	if err := os.WriteFile(path, []byte("ptla_rotated\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the change is visible even on file systems with coarse
+	// modification times.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := p.APIKey(t.Context()); err != nil || key != "ptla_rotated" {
+		t.Errorf("APIKey() after rotation = %q, %v; want ptla_rotated", key, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.APIKey(t.Context()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("APIKey() of a missing file: expected os.ErrNotExist, got %v", err)
+	}
+}
+
+func TestContextCredentials(t *testing.T) {
+	p := ContextCredentials(StaticCredentials("ptlc_default"))
+	ctx := t.Context()
+	if key, _ := p.APIKey(ctx); key != "ptlc_default" {
+		t.Errorf("APIKey() = %q, want the fallback", key)
+	}
+	if key, _ := p.APIKey(ContextWithAPIKey(ctx, "ptlc_user")); key != "ptlc_user" {
+		t.Errorf("APIKey() = %q, want the context key", key)
+	}
+	if key, _ := ContextCredentials(nil).APIKey(ctx); key != "" {
+		t.Errorf("APIKey() without fallback = %q, want empty", key)
+	}
+}
+
+func TestTransport_Credentials(t *testing.T) {
+	var got string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(headerAuth)
+		w.WriteHeader(http.StatusNoContent)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	errUnavailable := errors.New("vault unavailable")
+	tp := New(http.DefaultTransport, "unused", "v1", "test-agent",
+		WithCredentials(ContextCredentials(StaticCredentials("default-key"))),
+		WithPathCredentials("/broken/", CredentialFunc(func(context.Context) (string, error) {
+			return "", errUnavailable
+		})),
+	)
+	client := &http.Client{Transport: tp}
+
+	req, _ := http.NewRequestWithContext(ContextWithAPIKey(t.Context(), "user-key"), http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do failed: %v", err)
+	}
+	resp.Body.Close()
+	if got != "Bearer user-key" {
+		t.Errorf("Authorization = %q, want the context key", got)
+	}
+
+	if _, err := client.Get(server.URL + "/broken/"); !errors.Is(err, errUnavailable) {
+		t.Errorf("expected the provider error, got %v", err)
+	}
+}
+
+func TestRotatingCredentials(t *testing.T) {
+	r := NewRotatingCredentials("ptlc_first")
+	if key, _ := r.APIKey(t.Context()); key != "ptlc_first" {
+		t.Errorf("APIKey() = %q, want ptlc_first", key)
+	}
+	if old := r.Set("ptlc_second"); old != "ptlc_first" {
+		t.Errorf("Set() = %q, want the previous key", old)
+	}
+	if key, _ := r.APIKey(t.Context()); key != "ptlc_second" {
+		t.Errorf("APIKey() after Set = %q, want ptlc_second", key)
+	}
+}
@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes an API call to hooks.
+type RequestInfo struct {
+	// Operation is the library method that sent the request, such as
+	// "application.CreateServer". It is empty for unknown endpoints.
+	Operation string
+	// Method is the HTTP method.
+	Method string
+	// Path is the request path and query, with credentials redacted.
+	Path string
+	// ServerID is the identifier of the server the request is about, or its
+	// numeric ID for the Application API, if the path names one.
+	ServerID string
+}
+
+// Result is the outcome of an API call, passed to Hooks.AfterResponse.
+type Result struct {
+	// Response is the final response, or nil if Err is set. Hooks must not
+	// read its body.
+	Response *http.Response
+	// Err is the error returned to the caller, if any.
+	Err error
+	// Attempts is the number of times the request was sent.
+	Attempts int
+	// Duration is the time from BeforeRequest to the final response,
+	// including retry waits.
+	Duration time.Duration
+}
+
+// Hooks are callbacks around API calls, independent of any particular tracing
+// or metrics library. All fields are optional. Hooks are called synchronously
+// on the request's goroutine and must be safe for concurrent use.
+type Hooks struct {
+	// BeforeRequest is called once per call before the first attempt. The
+	// returned context is used for the request, all its attempts and the
+	// other hooks, for example to carry a span. Returning nil keeps ctx.
+	BeforeRequest func(ctx context.Context, info RequestInfo) context.Context
+	// Inject is called before every attempt to add propagation headers, such
+	// as a W3C traceparent, from the context to the request.
+	Inject func(ctx context.Context, header http.Header)
+	// OnRetry is called when an attempt failed and the request will be sent
+	// again after wait. attempt is the number of the failed attempt.
+	OnRetry func(ctx context.Context, info RequestInfo, attempt int, reason string, wait time.Duration)
+	// AfterResponse is called once per call with its final outcome.
+	AfterResponse func(ctx context.Context, info RequestInfo, result Result)
+}
+
+// WithHooks adds hooks to the Transport. Hooks added by several calls are all
+// called, in the order they were added.
+func WithHooks(h Hooks) TransportOption {
+	return func(t *Transport) {
+		t.hooks = append(t.hooks, h)
+	}
+}
+
+// call is the state of a request passed through RoundTrip, nil when nothing
+// observes the request.
+type call struct {
+	info     RequestInfo
+	attempts int
+}
+
+// attempt records the start of an attempt and injects propagation headers.
+func (t *Transport) attempt(req *http.Request, c *call, n int) {
+	if c == nil {
+		return
+	}
+	c.attempts = n
+	for _, h := range t.hooks {
+		if h.Inject != nil {
+			h.Inject(req.Context(), req.Header)
+		}
+	}
+}
+
+// retrying reports that a request is retried after wait.
+func (t *Transport) retrying(req *http.Request, c *call, attempt int, wait time.Duration, reason string) {
+	t.logRetry(req.Context(), req, attempt, wait, reason)
+	if c == nil {
+		return
+	}
+	for _, h := range t.hooks {
+		if h.OnRetry != nil {
+			h.OnRetry(req.Context(), c.info, attempt, reason, wait)
+		}
+	}
+}
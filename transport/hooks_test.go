@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type hookKey struct{}
+
+func TestTransport_Hooks(t *testing.T) {
+	var requests int32
+	var traceparents []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("Traceparent"))
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var (
+		before  RequestInfo
+		retries []string
+		after   Result
+		order   []string
+	)
+	hooks := Hooks{
+		BeforeRequest: func(ctx context.Context, info RequestInfo) context.Context {
+			before = info
+			order = append(order, "first")
+			return context.WithValue(ctx, hookKey{}, "span")
+		},
+		Inject: func(ctx context.Context, header http.Header) {
+			header.Set("Traceparent", ctx.Value(hookKey{}).(string))
+		},
+		OnRetry: func(ctx context.Context, info RequestInfo, attempt int, reason string, wait time.Duration) {
+			if ctx.Value(hookKey{}) != "span" {
+				t.Error("OnRetry did not get the context returned by BeforeRequest")
+			}
+			retries = append(retries, reason)
+		},
+		AfterResponse: func(ctx context.Context, info RequestInfo, result Result) {
+			if ctx.Value(hookKey{}) != "span" {
+				t.Error("AfterResponse did not get the context returned by BeforeRequest")
+			}
+			after = result
+		},
+	}
+	second := Hooks{
+		BeforeRequest: func(ctx context.Context, info RequestInfo) context.Context {
+			order = append(order, "second")
+			return nil
+		},
+	}
+	tp := New(http.DefaultTransport, "ptlc_key", "v1", "test-agent",
+		WithRetryWaitMin(time.Millisecond), WithRetryWaitMax(time.Millisecond),
+		WithRateLimiter(nil), WithHooks(hooks), WithHooks(second))
+	client := &http.Client{Transport: tp}
+
+	resp, err := client.Get(server.URL + "/api/client/servers/abc123/resources?token=secret")
+	if err != nil {
+		t.Fatalf("client.Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	want := RequestInfo{
+		Operation: "client.GetServerResources",
+		Method:    http.MethodGet,
+		Path:      "/api/client/servers/abc123/resources?token=REDACTED",
+		ServerID:  "abc123",
+	}
+	if before != want {
+		t.Errorf("BeforeRequest info = %+v, want %+v", before, want)
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("BeforeRequest order = %v, want [first second]", order)
+	}
+	if len(traceparents) != 2 || traceparents[0] != "span" || traceparents[1] != "span" {
+		t.Errorf("Traceparent headers = %q, want span on both attempts", traceparents)
+	}
+	if len(retries) != 1 || retries[0] != "server error" {
+		t.Errorf("OnRetry reasons = %q, want [server error]", retries)
+	}
+	if after.Attempts != 2 || after.Err != nil || after.Response == nil || after.Response.StatusCode != http.StatusNoContent {
+		t.Errorf("AfterResponse result = %+v, want 2 attempts ending in 204", after)
+	}
+}
+
+func TestTransport_HooksError(t *testing.T) {
+	var after Result
+	tp := New(http.DefaultTransport, "", "v1", "test-agent",
+		WithRateLimiter(nil),
+		WithPathKey("/api/application/", ""),
+		WithHooks(Hooks{AfterResponse: func(ctx context.Context, info RequestInfo, result Result) {
+			after = result
+		}}))
+
+	req := httptest.NewRequest(http.MethodGet, "http://panel.invalid/api/application/users", nil)
+	req.RequestURI = ""
+	if _, err := tp.RoundTrip(req); err == nil {
+		t.Fatal("expected an error")
+	}
+	if after.Err == nil || after.Attempts != 0 {
+		t.Errorf("AfterResponse result = %+v, want an error with no attempts", after)
+	}
+}
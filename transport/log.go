@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/idanyas/go-pterodactyl/internal/redact"
+)
+
+// WithLogger logs every attempt of every request to l, with its method, path,
+// status, duration, attempt number and rate limit headers, and every retry
+// with its reason and wait. Credentials are redacted. By default attempts are
+// logged at debug level and retries at warn level, see WithLogLevels.
+func WithLogger(l *slog.Logger) TransportOption {
+	return func(t *Transport) {
+		t.logger = l
+	}
+}
+
+// WithLogLevels sets the levels at which WithLogger logs request attempts and
+// retries.
+func WithLogLevels(request, retry slog.Level) TransportOption {
+	return func(t *Transport) {
+		t.requestLevel = request
+		t.retryLevel = retry
+	}
+}
+
+// logAttempt logs the outcome of one attempt of a request.
+func (t *Transport) logAttempt(req *http.Request, attempt int, start time.Time, resp *http.Response, err error) {
+	ctx := req.Context()
+	if t.logger == nil || !t.logger.Enabled(ctx, t.requestLevel) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", redact.URL(req.URL.RequestURI())),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", redact.String(err.Error())))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if rl := ParseRateLimit(resp); rl.Limit > 0 {
+			attrs = append(attrs, slog.Group("rate_limit",
+				slog.Int("limit", rl.Limit),
+				slog.Int("remaining", rl.Remaining),
+				slog.Time("reset", rl.Reset),
+			))
+		}
+	}
+	t.logger.LogAttrs(ctx, t.requestLevel, "pterodactyl request", attrs...)
+}
+
+// logRetry logs that a request is retried after wait.
+func (t *Transport) logRetry(ctx context.Context, req *http.Request, attempt int, wait time.Duration, reason string) {
+	if t.logger == nil {
+		return
+	}
+	t.logger.LogAttrs(ctx, t.retryLevel, "pterodactyl retry",
+		slog.String("method", req.Method),
+		slog.String("path", redact.URL(req.URL.RequestURI())),
+		slog.Int("attempt", attempt),
+		slog.String("reason", reason),
+		slog.Duration("wait", wait),
+	)
+}
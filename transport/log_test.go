@@ -0,0 +1,83 @@
+package transport
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransport_Logging(t *testing.T) {
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "240")
+		w.Header().Set("X-RateLimit-Remaining", "239")
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	tp := New(http.DefaultTransport, "ptlc_secret-key", "v1", "test-agent",
+		WithRetryWaitMin(time.Millisecond), WithRetryWaitMax(time.Millisecond),
+		WithLogger(logger), WithRateLimiter(nil))
+	client := &http.Client{Transport: tp}
+
+	resp, err := client.Get(server.URL + "/api/client/download?token=url-token")
+	if err != nil {
+		t.Fatalf("client.Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 log lines, got %d:\n%s", len(lines), logs.String())
+	}
+	wants := []string{
+		"level=DEBUG msg=\"pterodactyl request\" method=GET path=\"/api/client/download?token=REDACTED\" attempt=1",
+		"level=WARN msg=\"pterodactyl retry\" method=GET",
+		"level=DEBUG msg=\"pterodactyl request\" method=GET",
+	}
+	for i, want := range wants {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d = %q, want it to contain %q", i+1, lines[i], want)
+		}
+	}
+	for _, want := range []string{"status=502", "reason=\"server error\"", "status=200", "attempt=2", "rate_limit.limit=240 rate_limit.remaining=239"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("logs do not contain %q:\n%s", want, logs.String())
+		}
+	}
+	for _, secret := range []string{"ptlc_secret-key", "url-token"} {
+		if strings.Contains(logs.String(), secret) {
+			t.Errorf("logs contain %q", secret)
+		}
+	}
+}
+
+func TestTransport_LogLevels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	tp := New(http.DefaultTransport, "key", "v1", "test-agent",
+		WithLogger(logger), WithLogLevels(slog.LevelInfo, slog.LevelError))
+	resp, err := (&http.Client{Transport: tp}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("client.Get failed: %v", err)
+	}
+	resp.Body.Close()
+	if !strings.Contains(logs.String(), "level=INFO msg=\"pterodactyl request\"") {
+		t.Errorf("expected the request at info level, got:\n%s", logs.String())
+	}
+}
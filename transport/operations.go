@@ -0,0 +1,214 @@
+package transport
+
+import (
+	"net/http"
+	"strings"
+)
+
+// operation maps a request to the name of the library method that sends it.
+type operation struct {
+	method   string
+	segments []string // path below /api/; "{name}" matches any segment
+	name     string
+}
+
+// operations lists the endpoints of the Application and Client APIs. Routes
+// with a literal segment come before routes with a parameter in its place,
+// because the first match wins.
+var operations = parseOperations([][3]string{
+	// Application API
+	{"GET", "application/users", "application.ListUsers"},
+	{"POST", "application/users", "application.CreateUser"},
+	{"GET", "application/users/external/{external}", "application.GetUserExternal"},
+	{"GET", "application/users/{user}", "application.GetUser"},
+	{"PATCH", "application/users/{user}", "application.UpdateUser"},
+	{"DELETE", "application/users/{user}", "application.DeleteUser"},
+
+	{"GET", "application/nodes", "application.ListNodes"},
+	{"POST", "application/nodes", "application.CreateNode"},
+	{"GET", "application/nodes/deployable", "application.GetDeployableNodes"},
+	{"GET", "application/nodes/{node}", "application.GetNode"},
+	{"PATCH", "application/nodes/{node}", "application.UpdateNode"},
+	{"DELETE", "application/nodes/{node}", "application.DeleteNode"},
+	{"GET", "application/nodes/{node}/configuration", "application.GetNodeConfiguration"},
+	{"GET", "application/nodes/{node}/allocations", "application.ListNodeAllocations"},
+	{"POST", "application/nodes/{node}/allocations", "application.CreateNodeAllocations"},
+	{"DELETE", "application/nodes/{node}/allocations/{allocation}", "application.DeleteNodeAllocation"},
+
+	{"GET", "application/locations", "application.ListLocations"},
+	{"POST", "application/locations", "application.CreateLocation"},
+	{"GET", "application/locations/{location}", "application.GetLocation"},
+	{"PATCH", "application/locations/{location}", "application.UpdateLocation"},
+	{"DELETE", "application/locations/{location}", "application.DeleteLocation"},
+
+	{"GET", "application/nests", "application.ListNests"},
+	{"GET", "application/nests/{nest}", "application.GetNest"},
+	{"GET", "application/nests/{nest}/eggs", "application.ListNestEggs"},
+	{"GET", "application/nests/{nest}/eggs/{egg}", "application.GetEgg"},
+
+	{"GET", "application/servers", "application.ListServers"},
+	{"POST", "application/servers", "application.CreateServer"},
+	{"GET", "application/servers/external/{external}", "application.GetServerExternal"},
+	{"GET", "application/servers/{server}", "application.GetServer"},
+	{"DELETE", "application/servers/{server}", "application.DeleteServer"},
+	{"DELETE", "application/servers/{server}/force", "application.DeleteServer"},
+	{"PATCH", "application/servers/{server}/details", "application.UpdateServerDetails"},
+	{"PATCH", "application/servers/{server}/build", "application.UpdateServerBuild"},
+	{"PATCH", "application/servers/{server}/startup", "application.UpdateServerStartup"},
+	{"POST", "application/servers/{server}/suspend", "application.SuspendServer"},
+	{"POST", "application/servers/{server}/unsuspend", "application.UnsuspendServer"},
+	{"POST", "application/servers/{server}/reinstall", "application.ReinstallServer"},
+	{"GET", "application/servers/{server}/databases", "application.ListServerDatabases"},
+	{"POST", "application/servers/{server}/databases", "application.CreateServerDatabase"},
+	{"GET", "application/servers/{server}/databases/{database}", "application.GetServerDatabase"},
+	{"PATCH", "application/servers/{server}/databases/{database}", "application.UpdateServerDatabase"},
+	{"DELETE", "application/servers/{server}/databases/{database}", "application.DeleteServerDatabase"},
+	{"POST", "application/servers/{server}/databases/{database}/reset-password", "application.ResetServerDatabasePassword"},
+
+	// Client API
+	{"GET", "client", "client.ListServers"},
+	{"GET", "client/permissions", "client.GetSystemPermissions"},
+
+	{"GET", "client/account", "client.GetAccount"},
+	{"GET", "client/account/two-factor", "client.GetTwoFactorQR"},
+	{"POST", "client/account/two-factor", "client.EnableTwoFactor"},
+	{"POST", "client/account/two-factor/disable", "client.DisableTwoFactor"},
+	{"PUT", "client/account/email", "client.UpdateEmail"},
+	{"PUT", "client/account/password", "client.UpdatePassword"},
+	{"GET", "client/account/api-keys", "client.ListAPIKeys"},
+	{"POST", "client/account/api-keys", "client.CreateAPIKey"},
+	{"DELETE", "client/account/api-keys/{key}", "client.DeleteAPIKey"},
+	{"GET", "client/account/ssh-keys", "client.ListSSHKeys"},
+	{"POST", "client/account/ssh-keys", "client.AddSSHKey"},
+	{"POST", "client/account/ssh-keys/remove", "client.RemoveSSHKey"},
+	{"GET", "client/account/activity", "client.ListAccountActivity"},
+
+	{"GET", "client/servers/{server}", "client.GetServer"},
+	{"GET", "client/servers/{server}/resources", "client.GetServerResources"},
+	{"GET", "client/servers/{server}/websocket", "client.ConnectWebSocket"},
+	{"GET", "client/servers/{server}/activity", "client.ListServerActivity"},
+	{"POST", "client/servers/{server}/power", "client.SendPowerAction"},
+	{"POST", "client/servers/{server}/command", "client.SendCommand"},
+
+	{"GET", "client/servers/{server}/files/list", "client.ListFiles"},
+	{"GET", "client/servers/{server}/files/contents", "client.GetFileContents"},
+	{"POST", "client/servers/{server}/files/write", "client.WriteFile"},
+	{"POST", "client/servers/{server}/files/create-folder", "client.CreateDirectory"},
+	{"POST", "client/servers/{server}/files/delete", "client.DeleteFiles"},
+	{"PUT", "client/servers/{server}/files/rename", "client.RenameFile"},
+	{"POST", "client/servers/{server}/files/copy", "client.CopyFile"},
+	{"GET", "client/servers/{server}/files/download", "client.GetDownloadURL"},
+	{"GET", "client/servers/{server}/files/upload", "client.GetUploadURL"},
+	{"POST", "client/servers/{server}/files/compress", "client.CompressFiles"},
+	{"POST", "client/servers/{server}/files/decompress", "client.DecompressFile"},
+	{"POST", "client/servers/{server}/files/chmod", "client.ChmodFiles"},
+	{"POST", "client/servers/{server}/files/pull", "client.PullFile"},
+
+	{"GET", "client/servers/{server}/databases", "client.ListDatabases"},
+	{"POST", "client/servers/{server}/databases", "client.CreateDatabase"},
+	{"POST", "client/servers/{server}/databases/{database}/rotate-password", "client.RotateDatabasePassword"},
+	{"DELETE", "client/servers/{server}/databases/{database}", "client.DeleteDatabase"},
+
+	{"GET", "client/servers/{server}/schedules", "client.ListSchedules"},
+	{"POST", "client/servers/{server}/schedules", "client.CreateSchedule"},
+	{"GET", "client/servers/{server}/schedules/{schedule}", "client.GetSchedule"},
+	{"POST", "client/servers/{server}/schedules/{schedule}", "client.UpdateSchedule"},
+	{"DELETE", "client/servers/{server}/schedules/{schedule}", "client.DeleteSchedule"},
+	{"POST", "client/servers/{server}/schedules/{schedule}/execute", "client.ExecuteSchedule"},
+	{"POST", "client/servers/{server}/schedules/{schedule}/tasks", "client.CreateScheduleTask"},
+	{"POST", "client/servers/{server}/schedules/{schedule}/tasks/{task}", "client.UpdateScheduleTask"},
+	{"DELETE", "client/servers/{server}/schedules/{schedule}/tasks/{task}", "client.DeleteScheduleTask"},
+
+	{"GET", "client/servers/{server}/network/allocations", "client.ListAllocations"},
+	{"POST", "client/servers/{server}/network/allocations", "client.AssignAllocation"},
+	{"POST", "client/servers/{server}/network/allocations/{allocation}", "client.UpdateAllocationNotes"},
+	{"POST", "client/servers/{server}/network/allocations/{allocation}/primary", "client.SetPrimaryAllocation"},
+	{"DELETE", "client/servers/{server}/network/allocations/{allocation}", "client.DeleteAllocation"},
+
+	{"GET", "client/servers/{server}/users", "client.ListSubusers"},
+	{"POST", "client/servers/{server}/users", "client.CreateSubuser"},
+	{"GET", "client/servers/{server}/users/{user}", "client.GetSubuser"},
+	{"POST", "client/servers/{server}/users/{user}", "client.UpdateSubuser"},
+	{"DELETE", "client/servers/{server}/users/{user}", "client.DeleteSubuser"},
+
+	{"GET", "client/servers/{server}/backups", "client.ListBackups"},
+	{"POST", "client/servers/{server}/backups", "client.CreateBackup"},
+	{"GET", "client/servers/{server}/backups/{backup}", "client.GetBackup"},
+	{"DELETE", "client/servers/{server}/backups/{backup}", "client.DeleteBackup"},
+	{"GET", "client/servers/{server}/backups/{backup}/download", "client.GetBackupDownloadURL"},
+	{"POST", "client/servers/{server}/backups/{backup}/restore", "client.RestoreBackup"},
+	{"POST", "client/servers/{server}/backups/{backup}/lock", "client.ToggleBackupLock"},
+
+	{"GET", "client/servers/{server}/startup", "client.GetStartupConfig"},
+	{"PUT", "client/servers/{server}/startup/variable", "client.UpdateStartupVariable"},
+	{"POST", "client/servers/{server}/settings/rename", "client.RenameServer"},
+	{"POST", "client/servers/{server}/settings/reinstall", "client.ReinstallServer"},
+	{"PUT", "client/servers/{server}/settings/docker-image", "client.UpdateDockerImage"},
+})
+
+func parseOperations(routes [][3]string) []operation {
+	ops := make([]operation, len(routes))
+	for i, r := range routes {
+		ops[i] = operation{method: r[0], segments: strings.Split(r[1], "/"), name: r[2]}
+	}
+	return ops
+}
+
+// Operation returns the name of the library method that sends a request, such
+// as "application.CreateServer", and the server identifier or ID named in its
+// path. The name is empty for requests outside the known API endpoints.
+func Operation(req *http.Request) (name, serverID string) {
+	segments := apiSegments(req.URL.Path)
+	if segments == nil {
+		return "", ""
+	}
+	for _, op := range operations {
+		if op.method != req.Method || !matchSegments(op.segments, segments) {
+			continue
+		}
+		for i, s := range op.segments {
+			if s == "{server}" {
+				serverID = segments[i]
+			}
+		}
+		return op.name, serverID
+	}
+	return "", ""
+}
+
+// apiSegments returns the segments of a URL path below /api/, or nil if the
+// path is not below /api/.
+func apiSegments(path string) []string {
+	_, rest, ok := strings.Cut(path, "/api/")
+	if !ok {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(rest, "/"), "/")
+}
+
+// matchSegments reports whether path segments match a pattern in which
+// "{name}" matches any segment.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, s := range pattern {
+		if !strings.HasPrefix(s, "{") && s != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// OperationNames returns the names of all known operations.
+func OperationNames() []string {
+	names := make([]string, 0, len(operations))
+	seen := make(map[string]bool)
+	for _, op := range operations {
+		if !seen[op.name] {
+			seen[op.name] = true
+			names = append(names, op.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,60 @@
+package transport_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/idanyas/go-pterodactyl/application"
+	"github.com/idanyas/go-pterodactyl/client"
+	"github.com/idanyas/go-pterodactyl/transport"
+)
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		method, url    string
+		name, serverID string
+	}{
+		{"POST", "https://panel/api/application/servers", "application.CreateServer", ""},
+		{"GET", "https://panel/api/application/servers?page=2", "application.ListServers", ""},
+		{"GET", "https://panel/api/application/servers/external/ext-1", "application.GetServerExternal", ""},
+		{"PATCH", "https://panel/api/application/servers/7/build", "application.UpdateServerBuild", "7"},
+		{"DELETE", "https://panel/api/application/servers/7/force", "application.DeleteServer", "7"},
+		{"GET", "https://panel/api/application/nodes/deployable?memory=1&disk=1", "application.GetDeployableNodes", ""},
+		{"GET", "https://panel/api/application/nodes/3", "application.GetNode", ""},
+		{"GET", "https://panel/api/client", "client.ListServers", ""},
+		{"POST", "https://panel/api/client/servers/abc123/power", "client.SendPowerAction", "abc123"},
+		{"GET", "https://panel/api/client/servers/abc123/files/contents?file=%2Fa", "client.GetFileContents", "abc123"},
+		{"POST", "https://panel/api/client/servers/abc123/schedules/4/tasks/9", "client.UpdateScheduleTask", "abc123"},
+		{"GET", "https://panel.example.com/sub/api/client/account", "client.GetAccount", ""},
+		{"PUT", "https://panel/api/client/servers/abc123/power", "", ""},
+		{"GET", "https://panel/other", "", ""},
+	}
+	for _, tt := range tests {
+		name, serverID := transport.Operation(httptest.NewRequest(tt.method, tt.url, nil))
+		if name != tt.name || serverID != tt.serverID {
+			t.Errorf("Operation(%s %s) = %q, %q; want %q, %q", tt.method, tt.url, name, serverID, tt.name, tt.serverID)
+		}
+	}
+}
+
+// TestOperation_Names checks that every operation name is a method of the
+// client interfaces.
+func TestOperation_Names(t *testing.T) {
+	apis := map[string]reflect.Type{
+		"application": reflect.TypeOf((*application.ApplicationClient)(nil)).Elem(),
+		"client":      reflect.TypeOf((*client.ClientClient)(nil)).Elem(),
+	}
+	for _, name := range transport.OperationNames() {
+		api, method, _ := strings.Cut(name, ".")
+		typ, ok := apis[api]
+		if !ok {
+			t.Errorf("operation %q: unknown API %q", name, api)
+			continue
+		}
+		if _, ok := typ.MethodByName(method); !ok {
+			t.Errorf("operation %q: %s has no method %s", name, typ, method)
+		}
+	}
+}
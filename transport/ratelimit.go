@@ -0,0 +1,216 @@
+package transport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"sync"
+	"time"
+)
+
+// defaultRateLimitWindow is the period over which the panel counts requests
+// against X-RateLimit-Limit.
+const defaultRateLimitWindow = time.Minute
+
+// RateLimiterOption is a functional option for configuring a RateLimiter.
+type RateLimiterOption func(*RateLimiter)
+
+// WithRateLimitWindow sets the period over which the panel counts requests.
+// It defaults to one minute, matching the panel's throttle.
+func WithRateLimitWindow(d time.Duration) RateLimiterOption {
+	return func(l *RateLimiter) {
+		if d > 0 {
+			l.window = d
+		}
+	}
+}
+
+// RateLimiter paces requests using the rate limit headers of previous
+// responses. It keeps a token bucket for each key, as returned by
+// RateLimitKey, because the panel limits every user and each of its APIs
+// separately. A bucket holds X-RateLimit-Limit tokens, refills evenly over the
+// rate limit window, and is resynchronised with X-RateLimit-Remaining on every
+// response, so requests slow down before the panel starts rejecting them. When
+// a response carries X-RateLimit-Reset, the bucket instead stays empty until
+// that time and then refills completely.
+//
+// Until the first response for a key is seen the limiter does not delay its
+// requests. It is safe for concurrent use and may be shared by every request
+// made to the same panel.
+type RateLimiter struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// bucket is the token bucket of one key.
+type bucket struct {
+	limit  int
+	tokens float64
+	last   time.Time
+	reset  time.Time
+}
+
+// NewRateLimiter creates a RateLimiter.
+func NewRateLimiter(opts ...RateLimiterOption) *RateLimiter {
+	l := &RateLimiter{window: defaultRateLimitWindow, buckets: make(map[string]*bucket)}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// RateLimitKey returns the key under which a RateLimiter tracks requests to
+// an API path made with apiKey. It holds the API, such as "application" or
+// "client", and a hash of the API key.
+func RateLimitKey(path, apiKey string) string {
+	var api string
+	if segments := apiSegments(path); len(segments) > 0 {
+		api = segments[0]
+	}
+	return api + " " + keyHash(apiKey)
+}
+
+// keyHash returns a short hash of an API key, to keep keys out of map keys.
+func keyHash(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Wait blocks until a request for key may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, key string) error {
+	return l.wait(ctx, key, 0, false)
+}
+
+// wait is like Wait, but returns once it has waited for max in total, if max is
+// positive, even if no token is available. With failFast, it returns a
+// RateLimitedError instead of waiting past the context deadline, like the
+// retry waits of the Transport.
+func (l *RateLimiter) wait(ctx context.Context, key string, max time.Duration, failFast bool) error {
+	var deadline time.Time
+	if max > 0 {
+		deadline = time.Now().Add(max)
+	}
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		b := l.buckets[key]
+		if b == nil {
+			l.mu.Unlock()
+			return nil
+		}
+		b.refill(now, l.window)
+		if b.tokens >= 1 {
+			b.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate(l.window))
+		if !b.reset.IsZero() {
+			wait = b.reset.Sub(now)
+		}
+		if !deadline.IsZero() {
+			if !now.Before(deadline) {
+				l.mu.Unlock()
+				return nil
+			}
+			if until := deadline.Sub(now); until < wait {
+				wait = until
+			}
+		}
+		if ctxDeadline, ok := ctx.Deadline(); ok && failFast && ctxDeadline.Sub(now) < wait {
+			reset := b.reset
+			if reset.IsZero() {
+				reset = now.Add(wait)
+			}
+			l.mu.Unlock()
+			return &RateLimitedError{Reset: reset, RateLimit: RateLimitInfo{Limit: b.limit, Reset: reset}}
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Update resynchronises the bucket of key with the rate limit of a response.
+// Responses without rate limit headers are ignored.
+func (l *RateLimiter) Update(key string, info RateLimitInfo) {
+	if info.Limit <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.buckets[key]
+	if b == nil {
+		b = &bucket{}
+		l.buckets[key] = b
+	}
+	b.limit = info.Limit
+	b.tokens = math.Min(float64(info.Remaining), float64(info.Limit))
+	b.last = time.Now()
+	if !info.Reset.IsZero() {
+		b.reset = info.Reset
+	}
+}
+
+// State returns the current estimate of the rate limit of key. Remaining
+// includes tokens refilled since the last response. Reset is the last reset
+// time reported by the panel, if any.
+func (l *RateLimiter) State(key string) RateLimitInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.buckets[key]
+	if b == nil {
+		return RateLimitInfo{}
+	}
+	now := time.Now()
+	b.refill(now, l.window)
+	info := RateLimitInfo{
+		Limit:     b.limit,
+		Remaining: int(math.Max(b.tokens, 0)),
+	}
+	if b.reset.After(now) {
+		info.Reset = b.reset
+	}
+	return info
+}
+
+// refill adds the tokens accrued since the last update.
+func (b *bucket) refill(now time.Time, window time.Duration) {
+	switch {
+	case b.reset.IsZero():
+		b.tokens = math.Min(b.tokens+float64(now.Sub(b.last))*b.rate(window), float64(b.limit))
+	case !now.Before(b.reset):
+		// The panel announced when the window resets; nothing refills before.
+		b.tokens = float64(b.limit)
+		b.reset = time.Time{}
+	}
+	b.last = now
+}
+
+// rate returns the refill rate in tokens per nanosecond.
+func (b *bucket) rate(window time.Duration) float64 {
+	return float64(b.limit) / float64(window)
+}
+
+// RateLimitState returns the limiter's estimate of the rate limit of requests
+// to an API path made with ctx, using the API key the Transport would send.
+// It returns the zero value without a limiter or API key.
+func (t *Transport) RateLimitState(ctx context.Context, path string) RateLimitInfo {
+	if t.limiter == nil {
+		return RateLimitInfo{}
+	}
+	apiKey, err := t.key(ctx, path)
+	if err != nil {
+		return RateLimitInfo{}
+	}
+	return t.limiter.State(RateLimitKey(path, apiKey))
+}
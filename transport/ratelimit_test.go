@@ -0,0 +1,200 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Unknown(t *testing.T) {
+	l := NewRateLimiter()
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if err := l.Wait(context.Background(), ""); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Errorf("limiter without rate limit information waited %v", d)
+	}
+	if got := l.State(""); got != (RateLimitInfo{}) {
+		t.Errorf("State() = %+v, want zero", got)
+	}
+}
+
+func TestRateLimiter_Paces(t *testing.T) {
+	// Ten requests per second: one token every 100ms.
+	l := NewRateLimiter(WithRateLimitWindow(time.Second))
+	l.Update("", RateLimitInfo{Limit: 10, Remaining: 2})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background(), ""); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if d := time.Since(start); d < 80*time.Millisecond || d > 300*time.Millisecond {
+		t.Errorf("third request waited %v, want about 100ms", d)
+	}
+	if got := l.State(""); got.Limit != 10 || got.Remaining != 0 {
+		t.Errorf("State() = %+v, want limit 10 and remaining 0", got)
+	}
+}
+
+func TestRateLimiter_Reset(t *testing.T) {
+	l := NewRateLimiter()
+	reset := time.Now().Add(150 * time.Millisecond)
+	l.Update("", RateLimitInfo{Limit: 240, Remaining: 0, Reset: reset})
+
+	if got := l.State(""); !got.Reset.Equal(reset) || got.Remaining != 0 {
+		t.Errorf("State() = %+v, want remaining 0 until %v", got, reset)
+	}
+
+	start := time.Now()
+	if err := l.Wait(context.Background(), ""); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if d := time.Since(start); d < 100*time.Millisecond {
+		t.Errorf("request waited %v, want until the reset", d)
+	}
+	if got := l.State(""); got.Remaining != 239 || !got.Reset.IsZero() {
+		t.Errorf("State() = %+v, want a full bucket after the reset", got)
+	}
+}
+
+func TestRateLimiter_ContextCancel(t *testing.T) {
+	l := NewRateLimiter()
+	l.Update("", RateLimitInfo{Limit: 1, Remaining: 0, Reset: time.Now().Add(time.Hour)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, ""); err != context.DeadlineExceeded {
+		t.Errorf("Wait error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestTransport_RateLimiter(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		w.Header().Set("X-RateLimit-Limit", "20")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusOK)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	// Twenty requests per second once the bucket is empty: one every 50ms.
+	limiter := NewRateLimiter(WithRateLimitWindow(time.Second))
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent", WithRateLimiter(limiter))
+	client := &http.Client{Transport: tp}
+
+	// The first response tells the limiter the bucket is empty.
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("client.Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("client.Get failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if d := times[len(times)-1].Sub(times[0]); d < 100*time.Millisecond {
+		t.Errorf("4 requests took %v, want at least 100ms", d)
+	}
+	if got := limiter.State(RateLimitKey("/", "test-key")); got.Limit != 20 {
+		t.Errorf("State().Limit = %d, want 20", got.Limit)
+	}
+}
+
+func TestTransport_RateLimiter_MaxWait(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	limiter := NewRateLimiter()
+	limiter.Update(RateLimitKey("/", "test-key"), RateLimitInfo{Limit: 1, Remaining: 0, Reset: time.Now().Add(time.Hour)})
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent", WithRateLimiter(limiter))
+	client := &http.Client{Transport: tp}
+
+	ctx := WithRequestOptions(context.Background(), MaxRateLimitWait(50*time.Millisecond))
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do failed: %v", err)
+	}
+	resp.Body.Close()
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("request waited %v, want at most the rate limit max wait", d)
+	}
+}
+
+func TestTransport_RateLimiter_PerKey(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "1")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusOK)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	limiter := NewRateLimiter(WithRateLimitWindow(time.Hour))
+	tp := New(http.DefaultTransport, "key-a", "v1", "test-agent", WithRateLimiter(limiter),
+		WithCredentials(ContextCredentials(StaticCredentials("key-a"))))
+	client := &http.Client{Transport: tp}
+
+	get := func(ctx context.Context, path string) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	// key-a has no requests left on the Client API, which must not hold back
+	// other keys or the Application API.
+	if err := get(context.Background(), "/api/client"); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	if err := get(ContextWithAPIKey(context.Background(), "key-b"), "/api/client"); err != nil {
+		t.Errorf("request with another key failed: %v", err)
+	}
+	if err := get(context.Background(), "/api/application/users"); err != nil {
+		t.Errorf("request to the other API failed: %v", err)
+	}
+	if err := get(context.Background(), "/api/client"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("second request with the same key = %v, want ErrRateLimited", err)
+	}
+
+	if got := tp.RateLimitState(context.Background(), "/api/client"); got.Limit != 1 {
+		t.Errorf("RateLimitState().Limit = %d, want 1", got.Limit)
+	}
+	if got := tp.RateLimitState(ContextWithAPIKey(context.Background(), "key-c"), "/api/client"); got.Limit != 0 {
+		t.Errorf("RateLimitState() for an unused key = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,118 @@
+package transport
+
+import (
+	"context"
+	"time"
+)
+
+// requestOptionsKey is the context key for per-request options.
+type requestOptionsKey struct{}
+
+// requestConfig holds settings that override the Transport for one request.
+type requestConfig struct {
+	maxRetries         int
+	rateLimitMaxWait   time.Duration
+	retryNonIdempotent bool
+	retryGuard         RetryGuardFunc
+	response           *ResponseMeta
+	noCache            bool
+	noCoalescing       bool
+}
+
+// RetryGuardFunc decides whether a failed non-idempotent request may be sent
+// again. It is called after the backoff wait and typically checks whether the
+// first attempt took effect, for example by looking up a server by its
+// external ID. Returning false or an error stops retrying, and the failed
+// response or error of the last attempt is returned.
+type RetryGuardFunc func(ctx context.Context) (retry bool, err error)
+
+// RequestOption overrides Transport settings for requests made with a context.
+type RequestOption func(*requestConfig)
+
+// NoRetries makes a single attempt, returning 5xx and 429 responses without
+// waiting or retrying.
+func NoRetries() RequestOption {
+	return func(c *requestConfig) {
+		c.maxRetries = 1
+	}
+}
+
+// MaxRetries sets the maximum number of attempts, like WithMaxRetries.
+func MaxRetries(max int) RequestOption {
+	return func(c *requestConfig) {
+		if max > 0 {
+			c.maxRetries = max
+		}
+	}
+}
+
+// MaxRateLimitWait sets the maximum time to wait for a rate limit reset, like
+// WithRateLimitMaxWait.
+func MaxRateLimitWait(d time.Duration) RequestOption {
+	return func(c *requestConfig) {
+		if d > 0 {
+			c.rateLimitMaxWait = d
+		}
+	}
+}
+
+// RetryNonIdempotent allows retrying POST and PATCH requests, and requests
+// such as RenameFile that are not safe to repeat, after network errors and
+// 5xx responses. The panel does not deduplicate requests, so only use it for
+// operations that are safe to repeat.
+func RetryNonIdempotent() RequestOption {
+	return func(c *requestConfig) {
+		c.retryNonIdempotent = true
+	}
+}
+
+// RetryGuard allows retrying POST and PATCH requests after network errors and
+// 5xx responses when fn approves each retry.
+func RetryGuard(fn RetryGuardFunc) RequestOption {
+	return func(c *requestConfig) {
+		c.retryGuard = fn
+	}
+}
+
+// NoCache sends GET requests to the panel even if a Cache holds a fresh
+// response, and stores the new response.
+func NoCache() RequestOption {
+	return func(c *requestConfig) {
+		c.noCache = true
+	}
+}
+
+// WithRequestOptions returns a context that applies opts to requests made with
+// it, on top of any options already carried by ctx.
+//
+//	ctx = transport.WithRequestOptions(ctx, transport.NoRetries())
+//	server, err := client.Client().GetServer(ctx, id)
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	cfg, _ := ctx.Value(requestOptionsKey{}).(requestConfig)
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return context.WithValue(ctx, requestOptionsKey{}, cfg)
+}
+
+// config returns the settings for a request, applying context overrides.
+func (t *Transport) config(ctx context.Context) requestConfig {
+	cfg := requestConfig{
+		maxRetries:       t.maxRetries,
+		rateLimitMaxWait: t.rateLimitMaxWait,
+	}
+	if o, ok := ctx.Value(requestOptionsKey{}).(requestConfig); ok {
+		if o.maxRetries > 0 {
+			cfg.maxRetries = o.maxRetries
+		}
+		if o.rateLimitMaxWait > 0 {
+			cfg.rateLimitMaxWait = o.rateLimitMaxWait
+		}
+		cfg.retryNonIdempotent = o.retryNonIdempotent
+		cfg.retryGuard = o.retryGuard
+		cfg.response = o.response
+		cfg.noCache = o.noCache
+		cfg.noCoalescing = o.noCoalescing
+	}
+	return cfg
+}
@@ -0,0 +1,235 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransport_NoRetries(t *testing.T) {
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, `{"errors":[]}`)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent")
+	client := &http.Client{Transport: tp}
+	ctx := WithRequestOptions(context.Background(), NoRetries())
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != `{"errors":[]}` {
+		t.Errorf("body = %q, %v, want the error document", body, err)
+	}
+}
+
+func TestTransport_NoRetries_RateLimited(t *testing.T) {
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent")
+	client := &http.Client{Transport: tp}
+	ctx := WithRequestOptions(context.Background(), NoRetries())
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected status 429, got %s", resp.Status)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("request waited for the rate limit: %v", d)
+	}
+}
+
+func TestTransport_RequestOptions_Override(t *testing.T) {
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		count := atomic.AddInt32(&requests, 1)
+		if count == 1 {
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent",
+		WithRetryWaitMin(10*time.Millisecond), WithRetryWaitMax(10*time.Millisecond))
+	client := &http.Client{Transport: tp}
+
+	// Options from an outer context are kept and can be extended.
+	ctx := WithRequestOptions(context.Background(), MaxRateLimitWait(50*time.Millisecond))
+	ctx = WithRequestOptions(ctx, MaxRetries(5))
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if requests != 5 {
+		t.Errorf("expected 5 requests, got %d", requests)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("rate limit wait was not capped: %v", d)
+	}
+}
+
+func TestTransport_NonIdempotentRetries(t *testing.T) {
+	allow, deny := true, false
+	tests := []struct {
+		name   string
+		opts   []RequestOption
+		guard  *bool
+		want   int32
+		guards int32
+	}{
+		{name: "not retried by default", want: 1},
+		{name: "opt in", opts: []RequestOption{RetryNonIdempotent()}, want: 3},
+		{name: "guard allows", guard: &allow, want: 3, guards: 2},
+		{name: "guard stops", guard: &deny, want: 1, guards: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests, guarded int32
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(http.StatusBadGateway)
+				io.WriteString(w, "bad gateway")
+			}
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			defer server.Close()
+
+			opts := tt.opts
+			if tt.guard != nil {
+				opts = append(opts, RetryGuard(func(ctx context.Context) (bool, error) {
+					atomic.AddInt32(&guarded, 1)
+					return *tt.guard, nil
+				}))
+			}
+
+			tp := New(http.DefaultTransport, "test-key", "v1", "test-agent",
+				WithRetryWaitMin(time.Millisecond), WithRetryWaitMax(time.Millisecond))
+			client := &http.Client{Transport: tp}
+			ctx := WithRequestOptions(context.Background(), opts...)
+			req, _ := http.NewRequestWithContext(ctx, "POST", server.URL, strings.NewReader("{}"))
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("client.Do failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if requests != tt.want {
+				t.Errorf("expected %d requests, got %d", tt.want, requests)
+			}
+			if body, _ := io.ReadAll(resp.Body); string(body) != "bad gateway" {
+				t.Errorf("body = %q, want %q", body, "bad gateway")
+			}
+			if guarded != tt.guards {
+				t.Errorf("guard called %d times, want %d", guarded, tt.guards)
+			}
+		})
+	}
+}
+
+func TestTransport_PutRetries(t *testing.T) {
+	tests := []struct {
+		path string
+		want int32
+	}{
+		{"/api/client/servers/abc123/startup/variable", 3},
+		{"/api/client/servers/abc123/files/rename", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(http.StatusBadGateway)
+			}))
+			defer server.Close()
+
+			tp := New(http.DefaultTransport, "test-key", "v1", "test-agent",
+				WithRetryWaitMin(time.Millisecond), WithRetryWaitMax(time.Millisecond))
+			req, _ := http.NewRequest(http.MethodPut, server.URL+tt.path, strings.NewReader("{}"))
+			resp, err := (&http.Client{Transport: tp}).Do(req)
+			if err != nil {
+				t.Fatalf("client.Do failed: %v", err)
+			}
+			resp.Body.Close()
+			if requests != tt.want {
+				t.Errorf("expected %d requests, got %d", tt.want, requests)
+			}
+		})
+	}
+}
+
+func TestTransport_NonIdempotent_RateLimitRetried(t *testing.T) {
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent", WithRetryWaitMin(time.Millisecond))
+	client := &http.Client{Transport: tp}
+	req, _ := http.NewRequest("POST", server.URL, strings.NewReader("{}"))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated || requests != 2 {
+		t.Errorf("got status %d after %d requests, want 201 after 2", resp.StatusCode, requests)
+	}
+}
@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"net/http"
+	"time"
+)
+
+// ResponseMeta describes the final response of a request. Fill one in with the
+// CaptureResponse request option.
+type ResponseMeta struct {
+	// StatusCode is the HTTP status of the final response, or zero if no
+	// response was received.
+	StatusCode int
+	// Header holds the headers of the final response.
+	Header http.Header
+	// RateLimit is the rate limit state reported by the final response.
+	RateLimit RateLimitInfo
+	// RequestID is the X-Request-Id header set by the panel or a proxy in
+	// front of it, if any.
+	RequestID string
+	// Attempts is the number of times the request was sent.
+	Attempts int
+	// Duration is the time the request took, including retry waits.
+	Duration time.Duration
+}
+
+// CaptureResponse fills in meta when a request made with the context
+// completes, whether it succeeded or not. It lets callers of methods that only
+// return decoded models see the response behind them:
+//
+//	var meta transport.ResponseMeta
+//	ctx = transport.WithRequestOptions(ctx, transport.CaptureResponse(&meta))
+//	server, err := client.Client().GetServer(ctx, id)
+//	log.Println(meta.StatusCode, meta.RateLimit.Remaining)
+//
+// If the context is used for several requests, such as the pages of a list,
+// meta describes the last one to complete. meta must not be read while
+// requests made with the context are in flight.
+func CaptureResponse(meta *ResponseMeta) RequestOption {
+	return func(c *requestConfig) {
+		c.response = meta
+	}
+}
+
+// fill records the outcome of a request.
+func (m *ResponseMeta) fill(resp *http.Response, attempts int, duration time.Duration) {
+	*m = ResponseMeta{Attempts: attempts, Duration: duration}
+	if resp == nil {
+		return
+	}
+	m.StatusCode = resp.StatusCode
+	m.Header = resp.Header
+	m.RateLimit = ParseRateLimit(resp)
+	m.RequestID = resp.Header.Get("X-Request-Id")
+}
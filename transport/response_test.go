@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransport_CaptureResponse(t *testing.T) {
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.Header().Set("X-RateLimit-Limit", "240")
+		w.Header().Set("X-RateLimit-Remaining", "238")
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent",
+		WithRetryWaitMin(time.Millisecond), WithRetryWaitMax(time.Millisecond), WithRateLimiter(nil))
+	client := &http.Client{Transport: tp}
+
+	meta := ResponseMeta{StatusCode: -1, RequestID: "stale"}
+	ctx := WithRequestOptions(context.Background(), CaptureResponse(&meta))
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if meta.StatusCode != http.StatusOK || meta.RequestID != "req-123" || meta.Attempts != 2 {
+		t.Errorf("meta = %+v, want status 200, request ID req-123 and 2 attempts", meta)
+	}
+	if meta.RateLimit.Limit != 240 || meta.RateLimit.Remaining != 238 {
+		t.Errorf("meta.RateLimit = %+v, want 238 of 240 remaining", meta.RateLimit)
+	}
+	if meta.Header.Get("X-Request-Id") != "req-123" || meta.Duration <= 0 {
+		t.Errorf("meta = %+v, want headers and a duration", meta)
+	}
+}
+
+func TestTransport_CaptureResponseError(t *testing.T) {
+	tp := New(http.DefaultTransport, "", "v1", "test-agent",
+		WithRateLimiter(nil), WithCredentials(CredentialFunc(func(context.Context) (string, error) {
+			return "", errors.New("vault unavailable")
+		})))
+
+	meta := ResponseMeta{StatusCode: http.StatusOK}
+	ctx := WithRequestOptions(context.Background(), CaptureResponse(&meta))
+	req := httptest.NewRequest(http.MethodGet, "http://panel.invalid/api/client", nil).WithContext(ctx)
+	req.RequestURI = ""
+	if _, err := tp.RoundTrip(req); err == nil {
+		t.Fatal("expected an error")
+	}
+	if meta.StatusCode != 0 || meta.Header != nil || meta.Attempts != 0 {
+		t.Errorf("meta = %+v, want it reset with no response", meta)
+	}
+}
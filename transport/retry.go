@@ -0,0 +1,149 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited matches errors caused by the panel's rate limit, both
+// RateLimitedError and 429 responses returned by the client.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitedError is returned when a request was rejected with 429 Too Many
+// Requests, or held back by the RateLimiter after another request was, and
+// waiting for the rate limit to reset would outlast the request's context
+// deadline.
+type RateLimitedError struct {
+	// Reset is when the panel accepts requests again.
+	Reset time.Time
+	// RateLimit is the rate limit reported by the rejected response, or the
+	// RateLimiter's estimate if the request was not sent.
+	RateLimit RateLimitInfo
+}
+
+// Error implements the error interface.
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited until %s, after the context deadline", e.Reset.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrRateLimited.
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// ParseRetryAfter returns the time given by the Retry-After header of a
+// response, which holds either a number of seconds or an HTTP date.
+func ParseRetryAfter(r *http.Response) (time.Time, bool) {
+	if r == nil || r.Header == nil {
+		return time.Time{}, false
+	}
+	v := r.Header.Get("Retry-After")
+	if v == "" {
+		return time.Time{}, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Now().Add(time.Duration(secs) * time.Second), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// RetryBudget caps the total time spent waiting between retries by all
+// requests that share it, so an unhealthy panel cannot stall a whole client.
+// When the budget is spent, requests fail with the last response or error
+// instead of retrying. It is safe for concurrent use.
+type RetryBudget struct {
+	max    time.Duration
+	window time.Duration
+
+	mu    sync.Mutex
+	spent []retrySpend
+}
+
+// retrySpend is a wait charged to a RetryBudget.
+type retrySpend struct {
+	at time.Time
+	d  time.Duration
+}
+
+// NewRetryBudget creates a RetryBudget allowing at most max of retry waits in
+// any sliding window of the given length.
+func NewRetryBudget(max, window time.Duration) *RetryBudget {
+	return &RetryBudget{max: max, window: window}
+}
+
+// WithRetryBudget shares a RetryBudget between all requests of a Transport.
+func WithRetryBudget(b *RetryBudget) TransportOption {
+	return func(t *Transport) {
+		t.budget = b
+	}
+}
+
+// Remaining returns the retry wait time still available in the current window.
+func (b *RetryBudget) Remaining() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.max - b.usedLocked(time.Now())
+}
+
+// reserve charges d to the budget and reports whether it was available.
+func (b *RetryBudget) reserve(d time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if b.usedLocked(now)+d > b.max {
+		return false
+	}
+	b.spent = append(b.spent, retrySpend{at: now, d: d})
+	return true
+}
+
+// usedLocked drops waits outside the window and returns the total of the rest.
+func (b *RetryBudget) usedLocked(now time.Time) time.Duration {
+	i := 0
+	for i < len(b.spent) && now.Sub(b.spent[i].at) >= b.window {
+		i++
+	}
+	b.spent = b.spent[i:]
+
+	var used time.Duration
+	for _, s := range b.spent {
+		used += s.d
+	}
+	return used
+}
+
+var (
+	// errPastDeadline means a retry wait would outlast the context deadline.
+	errPastDeadline = errors.New("retry wait exceeds context deadline")
+	// errBudgetSpent means the retry budget cannot cover a retry wait.
+	errBudgetSpent = errors.New("retry budget exhausted")
+)
+
+// pause waits for d before a retry. It fails without waiting when the wait
+// would outlast the context deadline or exceed the retry budget, and returns
+// the context error if ctx is done while waiting.
+func (t *Transport) pause(ctx context.Context, d time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return errPastDeadline
+	}
+	if t.budget != nil && !t.budget.reserve(d) {
+		return errBudgetSpent
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,193 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	date := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{name: "seconds", header: "30", want: 30 * time.Second, ok: true},
+		{name: "http date", header: date.Format(http.TimeFormat), want: time.Hour, ok: true},
+		{name: "missing"},
+		{name: "negative", header: "-5"},
+		{name: "invalid", header: "soon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			got, ok := ParseRetryAfter(resp)
+			if ok != tt.ok {
+				t.Fatalf("ParseRetryAfter ok = %v, want %v", ok, tt.ok)
+			}
+			if d := time.Until(got); ok && (d > tt.want || d < tt.want-2*time.Second) {
+				t.Errorf("ParseRetryAfter = %v from now, want about %v", d, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransport_RetryAfter(t *testing.T) {
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent", WithRetryWaitMin(time.Millisecond))
+	client := &http.Client{Transport: tp}
+
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("client.Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if d := time.Since(start); d < 900*time.Millisecond {
+		t.Errorf("Retry-After was not honoured: retried after %v", d)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestTransport_RateLimitedError(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "240")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent")
+	client := &http.Client{Transport: tp}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+
+	start := time.Now()
+	_, err := client.Do(req)
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("request did not fail fast: %v", d)
+	}
+
+	var rateErr *RateLimitedError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("expected a RateLimitedError, got %v", err)
+	}
+	if d := time.Until(rateErr.Reset); d < 28*time.Second || d > 30*time.Second {
+		t.Errorf("Reset = %v from now, want about 30s", d)
+	}
+	if rateErr.RateLimit.Limit != 240 {
+		t.Errorf("RateLimit.Limit = %d, want 240", rateErr.RateLimit.Limit)
+	}
+}
+
+func TestTransport_RateLimiterFailFast(t *testing.T) {
+	var requests int32
+	reset := time.Now().Add(30 * time.Second)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("X-RateLimit-Limit", "240")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "test-key", "v1", "test-agent", WithRateLimiter(NewRateLimiter()))
+	client := &http.Client{Transport: tp}
+
+	for i := range 2 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+		start := time.Now()
+		_, err := client.Do(req)
+		cancel()
+		if d := time.Since(start); d > 500*time.Millisecond {
+			t.Errorf("request %d did not fail fast: %v", i+1, d)
+		}
+		var rateErr *RateLimitedError
+		if !errors.As(err, &rateErr) {
+			t.Fatalf("request %d: expected a RateLimitedError, got %v", i+1, err)
+		}
+		if rateErr.Reset.Unix() != reset.Unix() {
+			t.Errorf("request %d: Reset = %v, want %v", i+1, rateErr.Reset, reset)
+		}
+	}
+	// The second request is held back by the limiter without being sent.
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestTransport_RetryBudget(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget time.Duration
+		want   int32
+	}{
+		{name: "spent", budget: 0, want: 1},
+		{name: "available", budget: time.Second, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			defer server.Close()
+
+			budget := NewRetryBudget(tt.budget, time.Minute)
+			tp := New(http.DefaultTransport, "test-key", "v1", "test-agent",
+				WithRetryWaitMin(10*time.Millisecond), WithRetryWaitMax(10*time.Millisecond),
+				WithRetryBudget(budget))
+			client := &http.Client{Transport: tp}
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("client.Get failed: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusInternalServerError {
+				t.Errorf("expected status 500, got %s", resp.Status)
+			}
+			if requests != tt.want {
+				t.Errorf("expected %d requests, got %d", tt.want, requests)
+			}
+			if spent := tt.budget - budget.Remaining(); tt.want > 1 && spent < 20*time.Millisecond {
+				t.Errorf("budget charged %v, want at least 20ms", spent)
+			}
+		})
+	}
+}
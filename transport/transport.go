@@ -2,14 +2,18 @@
 package transport
 
 import (
-	"context"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"math"
 	"math/rand"
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/idanyas/go-pterodactyl/internal/redact"
 )
 
 const (
@@ -89,11 +93,21 @@ func WithRateLimitMaxWait(d time.Duration) TransportOption {
 	}
 }
 
+// WithRateLimiter paces requests with a RateLimiter, which tracks the rate
+// limit of each API key and API separately. Share one limiter between
+// transports that talk to the same panel.
+func WithRateLimiter(l *RateLimiter) TransportOption {
+	return func(t *Transport) {
+		t.limiter = l
+	}
+}
+
 // Transport is an http.RoundTripper that handles authentication,
 // rate limiting, and retries for the Pterodactyl API.
 type Transport struct {
 	base      http.RoundTripper
-	apiKey    string
+	creds     CredentialProvider
+	pathKeys  []pathKey
 	accept    string
 	userAgent string
 
@@ -102,19 +116,38 @@ type Transport struct {
 	retryWaitMin     time.Duration
 	retryWaitMax     time.Duration
 	rateLimitMaxWait time.Duration
+
+	// Proactive rate limiting, retry budget and circuit breaker, nil when
+	// disabled
+	limiter *RateLimiter
+	budget  *RetryBudget
+	breaker *CircuitBreaker
+
+	hooks []Hooks
+
+	// Response cache and in-flight GET requests, nil when disabled
+	cache   *Cache
+	flights *flightGroup
+
+	// Logging, disabled when logger is nil
+	logger       *slog.Logger
+	requestLevel slog.Level
+	retryLevel   slog.Level
 }
 
 // New creates a new Transport with optional configuration.
 func New(base http.RoundTripper, apiKey, apiVersion, userAgent string, opts ...TransportOption) *Transport {
 	t := &Transport{
 		base:             base,
-		apiKey:           apiKey,
+		creds:            StaticCredentials(apiKey),
 		accept:           fmt.Sprintf("Application/vnd.pterodactyl.%s+json", apiVersion),
 		userAgent:        userAgent,
 		maxRetries:       defaultMaxRetries,
 		retryWaitMin:     defaultRetryWaitMin,
 		retryWaitMax:     defaultRetryWaitMax,
 		rateLimitMaxWait: defaultRateLimitMaxWait,
+		requestLevel:     slog.LevelDebug,
+		retryLevel:       slog.LevelWarn,
 	}
 
 	for _, opt := range opts {
@@ -126,14 +159,85 @@ func New(base http.RoundTripper, apiKey, apiVersion, userAgent string, opts ...T
 
 // RoundTrip executes a single HTTP transaction, adding required headers and handling retries.
 func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	meta := t.config(req.Context()).response
+	if len(t.hooks) == 0 && meta == nil {
+		return t.roundTrip(req, nil)
+	}
+
+	c := &call{}
+	ctx := req.Context()
+	if len(t.hooks) > 0 {
+		c.info = RequestInfo{
+			Method: req.Method,
+			Path:   redact.URL(req.URL.RequestURI()),
+		}
+		c.info.Operation, c.info.ServerID = Operation(req)
+		for _, h := range t.hooks {
+			if h.BeforeRequest != nil {
+				if hctx := h.BeforeRequest(ctx, c.info); hctx != nil {
+					ctx = hctx
+				}
+			}
+		}
+		req = req.WithContext(ctx)
+	}
+
+	start := time.Now()
+	resp, err := t.roundTrip(req, c)
+	duration := time.Since(start)
+	if meta != nil {
+		meta.fill(resp, c.attempts, duration)
+	}
+	result := Result{Response: resp, Err: err, Attempts: c.attempts, Duration: duration}
+	for _, h := range t.hooks {
+		if h.AfterResponse != nil {
+			h.AfterResponse(ctx, c.info, result)
+		}
+	}
+	return resp, err
+}
+
+// roundTrip sends a request, retrying it as configured. c is nil when neither
+// hooks nor CaptureResponse observe the request.
+func (t *Transport) roundTrip(req *http.Request, c *call) (*http.Response, error) {
+	apiKey, err := t.key(req.Context(), req.URL.Path)
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Set(headerAccept, t.accept)
-	req.Header.Set(headerAuth, "Bearer "+t.apiKey)
+	req.Header.Set(headerAuth, "Bearer "+apiKey)
 	req.Header.Set(headerUA, t.userAgent)
 
-	var resp *http.Response
-	var err error
+	cfg := t.config(req.Context())
+	limitKey := RateLimitKey(req.URL.Path, apiKey)
+	fetch := func(req *http.Request, c *call) (*http.Response, error) {
+		if t.cache != nil {
+			return t.cache.roundTrip(req, apiKey, cfg.noCache, func(req *http.Request) (*http.Response, error) {
+				return t.send(req, c, cfg, limitKey)
+			})
+		}
+		return t.send(req, c, cfg, limitKey)
+	}
+	if t.flights != nil {
+		if key, ok := flightKey(req, apiKey, cfg); ok {
+			return t.flights.do(req, key, c, fetch)
+		}
+	}
+	return fetch(req, c)
+}
+
+// send sends a request, retrying it as configured. limitKey selects the
+// rate limiter bucket of the request.
+func (t *Transport) send(req *http.Request, c *call, cfg requestConfig, limitKey string) (*http.Response, error) {
+	retryable := isIdempotent(req) || cfg.retryNonIdempotent || cfg.retryGuard != nil
+
+	var (
+		resp *http.Response
+		err  error
+	)
+	for i := 0; i < cfg.maxRetries; i++ {
+		t.attempt(req, c, i+1)
 
-	for i := 0; i < t.maxRetries; i++ {
 		// Clone the request body if it exists
 		if req.Body != nil {
 			var bodyErr error
@@ -143,13 +247,48 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 			}
 		}
 
+		if t.limiter != nil {
+			if err := t.limiter.wait(req.Context(), limitKey, cfg.rateLimitMaxWait, true); err != nil {
+				return nil, err
+			}
+		}
+
+		var probe bool
+		if t.breaker != nil {
+			if probe, err = t.breaker.allow(req.URL.Host); err != nil {
+				return nil, err
+			}
+		}
+
+		start := time.Now()
 		resp, err = t.base.RoundTrip(req)
+		t.logAttempt(req, i+1, start, resp, err)
+		if t.breaker != nil {
+			t.breaker.done(req.Context(), req.URL.Host, probe, resp, err)
+		}
+		if err == nil && t.limiter != nil {
+			t.limiter.Update(limitKey, ParseRateLimit(resp))
+		}
+		last := i >= cfg.maxRetries-1
+
 		if err != nil {
-			// Network-level error, retry
-			if t.waitAndRetry(req.Context(), i) {
-				continue
+			// Network-level error, retry if the request is safe to repeat
+			if !retryable || last {
+				return nil, err
+			}
+			wait := t.backoff(i)
+			t.retrying(req, c, i+1, wait, "network error")
+			switch perr := t.pause(req.Context(), wait); perr {
+			case nil:
+			case errPastDeadline, errBudgetSpent:
+				return nil, err
+			default:
+				return nil, perr
+			}
+			if !guard(req, cfg) {
+				return nil, err
 			}
-			return nil, err
+			continue
 		}
 
 		// Success (2xx) or a non-retriable error (e.g., 4xx, except 429)
@@ -157,71 +296,126 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 			return resp, nil
 		}
 
-		// Handle 429 Too Many Requests
+		// Handle 429 Too Many Requests. The panel rejected the request
+		// without processing it, so it is retried regardless of method.
 		if resp.StatusCode == http.StatusTooManyRequests {
+			// Out of attempts: return the response so the caller sees the 429.
+			if last {
+				return resp, nil
+			}
+
 			rateLimit := ParseRateLimit(resp)
+			reset, ok := ParseRetryAfter(resp)
+			if !ok {
+				reset = rateLimit.Reset
+			}
 
 			// Calculate wait duration, ensuring it's not negative or too long
-			waitDuration := time.Until(rateLimit.Reset)
+			waitDuration := time.Until(reset)
 			if waitDuration <= 0 {
 				waitDuration = t.retryWaitMin
-			} else if waitDuration > t.rateLimitMaxWait {
-				waitDuration = t.rateLimitMaxWait
+			} else if waitDuration > cfg.rateLimitMaxWait {
+				waitDuration = cfg.rateLimitMaxWait
 			}
 
-			// Drain the body to allow connection reuse
-			io.Copy(io.Discard, resp.Body)
-			resp.Body.Close()
-
-			select {
-			case <-time.After(waitDuration):
-				continue // Retry the request
-			case <-req.Context().Done():
-				return nil, req.Context().Err()
+			t.retrying(req, c, i+1, waitDuration, "rate limited")
+			switch perr := t.pause(req.Context(), waitDuration); perr {
+			case nil:
+				// Drain the body to allow connection reuse
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				continue
+			case errPastDeadline:
+				resp.Body.Close()
+				if reset.IsZero() {
+					reset = time.Now().Add(waitDuration)
+				}
+				return nil, &RateLimitedError{Reset: reset, RateLimit: rateLimit}
+			case errBudgetSpent:
+				return resp, nil
+			default:
+				resp.Body.Close()
+				return nil, perr
 			}
 		}
 
 		// Handle 5xx Server Errors
 		if resp.StatusCode >= http.StatusInternalServerError {
-			// Drain the body before retrying
-			io.Copy(io.Discard, resp.Body)
-			resp.Body.Close()
+			// Out of attempts, or the request may have taken effect: return
+			// the response with its body intact.
+			if !retryable || last {
+				return resp, nil
+			}
 
-			if t.waitAndRetry(req.Context(), i) {
-				continue
+			// A 503 may say when the panel expects to recover.
+			wait := t.backoff(i)
+			if at, ok := ParseRetryAfter(resp); ok && resp.StatusCode == http.StatusServiceUnavailable {
+				wait = min(max(time.Until(at), 0), cfg.rateLimitMaxWait)
 			}
 
-			// Context was cancelled or we're out of retries
-			if req.Context().Err() != nil {
-				return nil, req.Context().Err()
+			// Buffer the body so the connection can be reused and the
+			// response returned if the retry is not sent.
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+
+			t.retrying(req, c, i+1, wait, "server error")
+			switch perr := t.pause(req.Context(), wait); perr {
+			case nil:
+			case errPastDeadline, errBudgetSpent:
+				return resp, nil
+			default:
+				return nil, perr
+			}
+			if !guard(req, cfg) {
+				return resp, nil
 			}
 		}
-
-		// If we are here, it means we ran out of retries for 5xx errors
-		break
 	}
 
 	return resp, err
 }
 
-// waitAndRetry calculates the backoff duration, waits, and returns true if a retry should be attempted.
-func (t *Transport) waitAndRetry(ctx context.Context, retryCount int) bool {
-	if retryCount >= t.maxRetries-1 {
-		return false
+// ErrNoAPIKey is returned for requests to an API that has no API key.
+var ErrNoAPIKey = errors.New("no API key configured")
+
+// unrepeatable lists operations whose method is idempotent but which are not
+// safe to repeat. A rename that failed halfway has moved some of its files,
+// and repeating it moves them again.
+var unrepeatable = map[string]bool{
+	"client.RenameFile": true,
+}
+
+// isIdempotent reports whether a request can be repeated without changing the
+// result, based on its method and operation.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	case http.MethodPut, http.MethodDelete:
+		name, _ := Operation(req)
+		return !unrepeatable[name]
+	}
+	return false
+}
+
+// guard reports whether the retry guard of a non-idempotent request, if any,
+// approves another attempt. The guard's own requests are not guarded.
+func guard(req *http.Request, cfg requestConfig) bool {
+	if cfg.retryGuard == nil || isIdempotent(req) {
+		return true
 	}
+	retry, err := cfg.retryGuard(WithRequestOptions(req.Context(), RetryGuard(nil)))
+	return err == nil && retry
+}
 
-	// Exponential backoff with jitter
+// backoff returns the wait before retry number retryCount+1: exponential
+// backoff with jitter, bounded by the retry wait settings.
+func (t *Transport) backoff(retryCount int) time.Duration {
 	backoff := float64(t.retryWaitMin) * math.Pow(2, float64(retryCount))
 	if backoff > float64(t.retryWaitMax) {
 		backoff = float64(t.retryWaitMax)
 	}
 	backoff *= (1 + rand.Float64()*0.5) // Add up to 50% jitter
-	wait := time.Duration(backoff)
-
-	select {
-	case <-time.After(wait):
-		return true
-	case <-ctx.Done():
-		return false
-	}
+	return time.Duration(backoff)
 }
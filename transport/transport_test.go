@@ -2,6 +2,7 @@ package transport
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -77,7 +78,9 @@ func TestTransport_Retry(t *testing.T) {
 
 func TestTransport_RateLimit(t *testing.T) {
 	var requests int32
-	resetTime := time.Now().Add(200 * time.Millisecond)
+	// X-RateLimit-Reset has a resolution of one second, so this resets
+	// between 200ms and 1.2s from now.
+	resetTime := time.Now().Add(1200 * time.Millisecond)
 
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		count := atomic.AddInt32(&requests, 1)
@@ -133,12 +136,19 @@ func TestTransport_Retry_ContextCancel(t *testing.T) {
 
 	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
 
-	_, err := client.Do(req)
-	if err == nil {
-		t.Fatal("expected an error, got nil")
+	// The backoff would outlast the deadline, so the 500 is returned at once
+	// instead of a context error.
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %s", resp.Status)
 	}
-	if err != context.DeadlineExceeded {
-		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	if d := time.Since(start); d >= 80*time.Millisecond {
+		t.Errorf("request took %v, want it to return before the deadline", d)
 	}
 }
 
@@ -280,3 +290,43 @@ func TestTransport_RateLimitMaxWait(t *testing.T) {
 		t.Errorf("expected wait time around 100ms, got %v", duration)
 	}
 }
+
+func TestTransport_PathKeys(t *testing.T) {
+	var got []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Path+" "+r.Header.Get(headerAuth))
+		w.WriteHeader(http.StatusNoContent)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	tp := New(http.DefaultTransport, "default-key", "v1", "test-agent",
+		WithPathKey("/api/", "api-key"),
+		WithPathKey("/api/client/", "client-key"),
+		WithPathKey("/api/application/", ""),
+	)
+	client := &http.Client{Transport: tp}
+
+	for _, path := range []string{"/other", "/api/nests", "/api/client/servers"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("client.Get(%s) failed: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+	want := []string{
+		"/other Bearer default-key",
+		"/api/nests Bearer api-key",
+		"/api/client/servers Bearer client-key",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+
+	if _, err := client.Get(server.URL + "/api/application/users"); !errors.Is(err, ErrNoAPIKey) {
+		t.Errorf("expected ErrNoAPIKey, got %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("request without a key reached the server")
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"sync"
 	"time"
 
 	"github.com/coder/websocket"
+	"github.com/idanyas/go-pterodactyl/internal/redact"
 	"github.com/idanyas/go-pterodactyl/models"
 )
 
@@ -75,6 +77,17 @@ func DefaultReconnectOptions() ReconnectOptions {
 	}
 }
 
+// ConnOption is a functional option for configuring a Conn.
+type ConnOption func(*Conn)
+
+// WithLogger logs connection lifecycle events to l: connecting, disconnects,
+// reconnection attempts, token expiry and closing. Tokens are never logged.
+func WithLogger(l *slog.Logger) ConnOption {
+	return func(ws *Conn) {
+		ws.logger = l
+	}
+}
+
 // Conn represents an active WebSocket connection to a server.
 type Conn struct {
 	socketURL string
@@ -84,6 +97,7 @@ type Conn struct {
 	cancel    context.CancelFunc
 	eventChan chan Event
 	closeOnce sync.Once
+	logger    *slog.Logger
 
 	// Reconnection
 	reconnectOpts ReconnectOptions
@@ -93,7 +107,7 @@ type Conn struct {
 
 // NewConn establishes a new WebSocket connection with optional reconnection.
 // Pass nil for reconnectOpts to disable automatic reconnection.
-func NewConn(ctx context.Context, socketURL, token string, reconnectOpts *ReconnectOptions) (*Conn, error) {
+func NewConn(ctx context.Context, socketURL, token string, reconnectOpts *ReconnectOptions, opts ...ConnOption) (*Conn, error) {
 	wsConnCtx, cancel := context.WithCancel(context.Background())
 
 	ws := &Conn{
@@ -107,11 +121,16 @@ func NewConn(ctx context.Context, socketURL, token string, reconnectOpts *Reconn
 	if reconnectOpts != nil {
 		ws.reconnectOpts = *reconnectOpts
 	}
+	for _, opt := range opts {
+		opt(ws)
+	}
 
 	if err := ws.connect(ctx); err != nil {
 		cancel()
+		ws.log(slog.LevelWarn, "websocket connect failed", slog.String("error", err.Error()))
 		return nil, err
 	}
+	ws.log(slog.LevelInfo, "websocket connected")
 
 	go ws.readLoop()
 
@@ -162,6 +181,10 @@ func (ws *Conn) readLoop() {
 
 		_, data, err := conn.Read(ws.ctx)
 		if err != nil {
+			if ws.ctx.Err() == nil {
+				ws.log(slog.LevelWarn, "websocket disconnected", slog.String("error", err.Error()))
+			}
+
 			// Check if we should reconnect
 			if ws.reconnectOpts.Enable && !ws.reconnecting {
 				ws.mu.Lock()
@@ -175,6 +198,7 @@ func (ws *Conn) readLoop() {
 				for {
 					if ws.reconnectOpts.MaxAttempts > 0 && attempt >= ws.reconnectOpts.MaxAttempts {
 						// Max attempts reached
+						ws.log(slog.LevelError, "websocket reconnection failed", slog.Int("attempts", attempt))
 						ws.mu.Lock()
 						ws.reconnecting = false
 						ws.mu.Unlock()
@@ -188,12 +212,14 @@ func (ws *Conn) readLoop() {
 						attempt++
 
 						// Try to reconnect
+						ws.log(slog.LevelInfo, "websocket reconnecting", slog.Int("attempt", attempt), slog.Duration("delay", backoff))
 						ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 						err := ws.connect(ctx)
 						cancel()
 
 						if err == nil {
 							// Reconnected successfully
+							ws.log(slog.LevelInfo, "websocket reconnected", slog.Int("attempt", attempt))
 							ws.mu.Lock()
 							ws.reconnecting = false
 							ws.mu.Unlock()
@@ -250,6 +276,7 @@ func (ws *Conn) readLoop() {
 				event = &StatusEvent{Status: msg.Args[0]}
 			}
 		case "jwt error", "token expiring", "token expired":
+			ws.log(slog.LevelInfo, "websocket token expiring", slog.String("event", msg.Event))
 			event = &TokenExpiredEvent{}
 		}
 
@@ -313,9 +340,19 @@ func (ws *Conn) Close() {
 		if conn != nil {
 			conn.Close(websocket.StatusNormalClosure, "")
 		}
+		ws.log(slog.LevelInfo, "websocket closed")
 	})
 }
 
+// log logs a lifecycle event with the socket URL if a logger is set.
+func (ws *Conn) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	if ws.logger == nil {
+		return
+	}
+	attrs = append([]slog.Attr{slog.String("socket", redact.URL(ws.socketURL))}, attrs...)
+	ws.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
 // IsReconnecting returns true if the connection is currently attempting to reconnect.
 func (ws *Conn) IsReconnecting() bool {
 	ws.mu.RLock()
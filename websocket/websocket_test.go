@@ -1,8 +1,10 @@
 package websocket
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -93,3 +95,43 @@ func TestWebSocket_ConnectAndReceive(t *testing.T) {
 		t.Errorf("did not receive all expected events (status: %v, console: %v)", receivedStatus, receivedConsole)
 	}
 }
+
+func TestWebSocket_Logging(t *testing.T) {
+	wsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			t.Logf("websocket accept error: %v", err)
+			return
+		}
+		if _, _, err := c.Read(r.Context()); err != nil {
+			return
+		}
+		data, _ := json.Marshal(message{Event: "token expiring"})
+		c.Write(r.Context(), websocket.MessageText, data)
+		c.Close(websocket.StatusGoingAway, "shutting down")
+	}))
+	defer wsServer.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	ws, err := NewConn(context.Background(), "ws"+strings.TrimPrefix(wsServer.URL, "http")+"?token=url-token", "secret-token", nil, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewConn failed: %v", err)
+	}
+	for range ws.Events() {
+		// Drain until the server closes the connection.
+	}
+	ws.Close()
+
+	out := logs.String()
+	for _, want := range []string{"websocket connected", "websocket token expiring", "websocket disconnected", "websocket closed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("logs do not contain %q:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"secret-token", "url-token"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("logs contain %q:\n%s", secret, out)
+		}
+	}
+}